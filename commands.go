@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"noir-backend/migrations"
+	"noir-backend/seeder"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const usage = `usage: noir <command> [flags]
+
+commands:
+  serve                                  start the HTTP server (default)
+  migrate up [-dir DIR]                  apply pending migrations
+  migrate down [-dir DIR] [-steps N]     revert the last N migrations (default 1)
+  migrate version                        print the applied schema version
+  seed admin                             create the admin from ADMIN_EMAIL/ADMIN_PASSWORD
+  seed movies -from-file PATH            import TMDB fixtures from a file or directory
+  seed movies -fetch -out PATH           download TMDB fixtures (needs network and TMDB_API_KEY)
+  expire-transactions                    cancel unpaid transactions past their deadline
+  create-user -email E -role R           password from NOIR_USER_PASSWORD or piped on stdin
+`
+
+func runCommand(args []string) error {
+	if len(args) == 0 {
+		return serve()
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "serve":
+		return serve()
+	case "migrate":
+		return migrateCommand(ctx, args[1:])
+	case "seed":
+		return seedCommand(ctx, args[1:])
+	case "expire-transactions":
+		return expireTransactionsCommand(ctx)
+	case "create-user":
+		return createUserCommand(ctx, args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func migrateCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up|down|version [-dir DIR] [-steps N]")
+	}
+
+	flags := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	dir := flags.String("dir", "", "read migrations from this directory instead of the embedded ones")
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	flags.Parse(args[1:])
+
+	source := fs.FS(migrations.FS)
+	if *dir != "" {
+		source = os.DirFS(*dir)
+	}
+	all, err := utils.LoadMigrations(source)
+	if err != nil {
+		return err
+	}
+
+	return withDB(func(db *pgxpool.Pool) error {
+		switch args[0] {
+		case "up":
+			applied, err := utils.MigrateUp(ctx, db, all)
+			log.Printf("applied %d migration(s)", applied)
+			return err
+		case "down":
+			reverted, err := utils.MigrateDown(ctx, db, all, *steps)
+			log.Printf("reverted %d migration(s)", reverted)
+			return err
+		case "version":
+			if len(all) == 0 {
+				return errors.New("no migrations found")
+			}
+			version, dirty, err := utils.SchemaVersion(ctx, db)
+			if err != nil {
+				return err
+			}
+			log.Printf("schema version %d (dirty: %t), latest %d", version, dirty, all[len(all)-1].Version)
+			return nil
+		default:
+			return fmt.Errorf("unknown migrate direction %q", args[0])
+		}
+	})
+}
+
+func seedCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: seed admin|movies")
+	}
+
+	switch args[0] {
+	case "admin":
+		return withDB(func(db *pgxpool.Pool) error {
+			return seeder.SeedAdminUser(ctx, db)
+		})
+	case "movies":
+		flags := flag.NewFlagSet("seed movies", flag.ExitOnError)
+		fromFile := flags.String("from-file", "", "TMDB fixture file or directory to import")
+		fetch := flags.Bool("fetch", false, "download fixtures from TMDB instead of importing")
+		out := flags.String("out", "seeder/fixtures/tmdb_movies.json", "where -fetch writes the fixtures")
+		categories := flags.String("categories", "now_playing,upcoming", "comma-separated TMDB lists to fetch")
+		flags.Parse(args[1:])
+
+		if *fetch {
+			count, err := seeder.FetchTMDBFixtures(ctx, strings.Split(*categories, ","), *out)
+			if err != nil {
+				return err
+			}
+			log.Printf("wrote %d movie(s) to %s", count, *out)
+			return nil
+		}
+
+		if *fromFile == "" {
+			return errors.New("seed movies needs -from-file PATH or -fetch")
+		}
+		return withDB(func(db *pgxpool.Pool) error {
+			result, err := seeder.ImportTMDBMovies(ctx, db, *fromFile)
+			if err != nil {
+				return err
+			}
+			log.Printf("movies created: %d, updated: %d, skipped: %d", result.Created, result.Updated, result.Skipped)
+			return nil
+		})
+	default:
+		return fmt.Errorf("unknown seed target %q", args[0])
+	}
+}
+
+func expireTransactionsCommand(ctx context.Context) error {
+	return withDB(func(db *pgxpool.Pool) error {
+		expired, err := services.NewTransactionService(db).ExpirePendingTransactions(ctx)
+		log.Printf("expired %d transaction(s)", expired)
+		return err
+	})
+}
+
+func createUserCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("create-user", flag.ExitOnError)
+	email := flags.String("email", "", "email of the new user")
+	role := flags.String("role", utils.RoleUser, "user, admin, cinema_manager, usher or support")
+	flags.Parse(args)
+
+	password, err := readPassword()
+	if err != nil {
+		return err
+	}
+
+	return withDB(func(db *pgxpool.Pool) error {
+		userID, err := seeder.CreateUser(ctx, db, *email, password, *role)
+		if err != nil {
+			return err
+		}
+		log.Printf("created %s user %s with id %d", *role, *email, userID)
+		return nil
+	})
+}
+
+// readPassword takes the password from NOIR_USER_PASSWORD or the first line
+// piped on stdin, never from argv where it would show in ps and shell history.
+// A terminal is refused because the password would be echoed.
+func readPassword() (string, error) {
+	if password := os.Getenv("NOIR_USER_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	if info.Mode()&os.ModeCharDevice != 0 {
+		return "", errors.New("set NOIR_USER_PASSWORD or pipe the password on stdin")
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+	return password, nil
+}
+
+func withDB(fn func(db *pgxpool.Pool) error) error {
+	db, err := utils.ConnectDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return fn(db)
+}
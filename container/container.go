@@ -15,18 +15,65 @@ type Container struct {
 	MovieController       *controllers.MovieController
 	TransactionService    *services.TransactionService
 	TransactionController *controllers.TransactionController
+	UserService           *services.UserService
+	UserController        *controllers.UserController
+	SearchService         *services.SearchService
+	SearchController      *controllers.SearchController
+	PeopleService         *services.PeopleService
+	PeopleController      *controllers.PeopleController
+	GenreService          *services.GenreService
+	GenreController       *controllers.GenreController
+	ReviewService         *services.ReviewService
+	ReviewController      *controllers.ReviewController
+	WatchlistService      *services.WatchlistService
+	WatchlistController   *controllers.WatchlistController
+	ShowtimeService       *services.ShowtimeService
+	ShowtimeController    *controllers.ShowtimeController
+	ReportService         *services.ReportService
+	ReportController      *controllers.ReportController
+	AuditService          *services.AuditService
+	AuditController       *controllers.AuditController
 }
 
 func NewContainer(db *pgxpool.Pool, redis *redis.Client) *Container {
 	authService := services.NewAuthService(db, redis)
 	authController := controllers.NewAuthController(authService)
 
-	movieService := services.NewMovieService(db)
+	cache := services.NewCache(redis)
+
+	movieService := services.NewMovieService(db, cache)
 	movieController := controllers.NewMovieController(movieService)
 
 	transactionService := services.NewTransactionService(db)
 	transactionController := controllers.NewTransactionController(transactionService)
 
+	userService := services.NewUserService(db, redis)
+	userController := controllers.NewUserController(userService, transactionService)
+
+	searchService := services.NewSearchService(db, redis)
+	searchController := controllers.NewSearchController(searchService)
+
+	peopleService := services.NewPeopleService(db, cache)
+	peopleController := controllers.NewPeopleController(peopleService)
+
+	genreService := services.NewGenreService(db, cache)
+	genreController := controllers.NewGenreController(genreService)
+
+	reviewService := services.NewReviewService(db, cache)
+	reviewController := controllers.NewReviewController(reviewService)
+
+	watchlistService := services.NewWatchlistService(db)
+	watchlistController := controllers.NewWatchlistController(watchlistService)
+
+	showtimeService := services.NewShowtimeService(db)
+	showtimeController := controllers.NewShowtimeController(showtimeService)
+
+	reportService := services.NewReportService(db, cache)
+	reportController := controllers.NewReportController(reportService)
+
+	auditService := services.NewAuditService(db)
+	auditController := controllers.NewAuditController(auditService)
+
 	return &Container{
 		AuthService:           authService,
 		AuthController:        authController,
@@ -34,5 +81,23 @@ func NewContainer(db *pgxpool.Pool, redis *redis.Client) *Container {
 		MovieController:       movieController,
 		TransactionService:    transactionService,
 		TransactionController: transactionController,
+		UserService:           userService,
+		UserController:        userController,
+		SearchService:         searchService,
+		SearchController:      searchController,
+		PeopleService:         peopleService,
+		PeopleController:      peopleController,
+		GenreService:          genreService,
+		GenreController:       genreController,
+		ReviewService:         reviewService,
+		ReviewController:      reviewController,
+		WatchlistService:      watchlistService,
+		WatchlistController:   watchlistController,
+		ShowtimeService:       showtimeService,
+		ShowtimeController:    showtimeController,
+		ReportService:         reportService,
+		ReportController:      reportController,
+		AuditService:          auditService,
+		AuditController:       auditController,
 	}
 }
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	auditService *services.AuditService
+}
+
+func NewAuditController(auditService *services.AuditService) *AuditController {
+	return &AuditController{auditService: auditService}
+}
+
+// List Audit Logs godoc
+// @Summary List audit logs
+// @Description Browse the append-only audit log, newest first
+// @Tags admin
+// @Produce json
+// @Param actor_id query int false "User who performed the action"
+// @Param action query string false "Action, or a family such as movie."
+// @Param entity_type query string false "Entity type, e.g. movie, user, transaction"
+// @Param entity_id query string false "Entity id"
+// @Param request_id query string false "Request id"
+// @Param from query string false "On or after, YYYY-MM-DD"
+// @Param to query string false "On or before, YYYY-MM-DD"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Audit logs retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Something went wrong"
+// @Router /admin/audit-logs [get]
+func (c *AuditController) ListAuditLogs(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+	offset := (page - 1) * limit
+
+	filter := dto.AuditLogFilter{
+		Action:     ctx.Query("action"),
+		EntityType: ctx.Query("entity_type"),
+		EntityID:   ctx.Query("entity_id"),
+		RequestID:  ctx.Query("request_id"),
+	}
+	if actorID := ctx.Query("actor_id"); actorID != "" {
+		id, err := strconv.Atoi(actorID)
+		if err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "Invalid actor ID")
+			return
+		}
+		filter.ActorID = id
+	}
+	if from := ctx.Query("from"); from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "from must be formatted as YYYY-MM-DD")
+			return
+		}
+		filter.From = &date
+	}
+	if to := ctx.Query("to"); to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "to must be formatted as YYYY-MM-DD")
+			return
+		}
+		date = date.AddDate(0, 0, 1)
+		filter.To = &date
+	}
+
+	logs, total, err := c.auditService.ListAuditLogs(ctx.Request.Context(), filter, limit, offset)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pagination := dto.NewPagination(ctx, total, page, limit)
+	response := dto.PagedAuditLogsResponse{
+		PageInfo: pagination,
+		Result:   logs,
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "Audit logs retrieved successfully", response)
+}
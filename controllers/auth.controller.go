@@ -102,6 +102,36 @@ func (c *AuthController) GetProfile(ctx *gin.Context) {
 	utils.SendSuccess(ctx, http.StatusOK, "data retrieved successfully", user)
 }
 
+// UpdateProfile godoc
+// @Summary Update user profile
+// @Description Update name, phone number and birth date (YYYY-MM-DD) of current user. The birth date can only be set once.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateProfileRequest true "Profile fields"
+// @Security Token
+// @Success 200 {object} models.Profile
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse "Birth date already set"
+// @Router /profile [patch]
+func (c *AuthController) UpdateProfile(ctx *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	profile, status, err := c.authService.UpdateProfile(ctx.Request.Context(), ctx.GetInt("user_id"), req)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "profile updated successfully", profile)
+}
+
 // Logout godoc
 // @Summary Logout user
 // @Description Logout user by blacklisting refresh token
@@ -182,3 +212,210 @@ func (c *AuthController) ResetPassword(ctx *gin.Context) {
 
 	utils.SendSuccess(ctx, http.StatusOK, "password reset successfully", nil)
 }
+
+// Login Two Factor godoc
+// @Summary Complete login with two-factor code
+// @Description Exchange the login challenge token and a TOTP or recovery code for an access token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorLoginRequest true "Two-factor login request"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/login/2fa [post]
+func (c *AuthController) LoginTwoFactor(ctx *gin.Context) {
+	var req dto.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := c.authService.VerifyTwoFactorLogin(ctx.Request.Context(), req)
+	if err != nil {
+		utils.SendError(ctx, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "login successful", response)
+}
+
+// Enroll Two Factor godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and otpauth URI for the current user
+// @Tags auth
+// @Produce json
+// @Security Token
+// @Success 200 {object} dto.TwoFactorEnrollResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /auth/2fa/enroll [post]
+func (c *AuthController) EnrollTwoFactor(ctx *gin.Context) {
+	response, status, err := c.authService.EnrollTwoFactor(ctx.Request.Context(), ctx.GetInt("user_id"))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "scan the otpauth uri with your authenticator app", response)
+}
+
+// Confirm Two Factor godoc
+// @Summary Confirm two-factor enrollment
+// @Description Enable two-factor authentication with a code from the authenticator app and receive recovery codes
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "Two-factor code"
+// @Security Token
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/2fa/confirm [post]
+func (c *AuthController) ConfirmTwoFactor(ctx *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, status, err := c.authService.ConfirmTwoFactor(ctx.Request.Context(), ctx.GetInt("user_id"), req.Code)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "two-factor authentication enabled", response)
+}
+
+// Disable Two Factor godoc
+// @Summary Disable two-factor authentication
+// @Description Disable two-factor authentication for the current user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "Two-factor code"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /auth/2fa/disable [post]
+func (c *AuthController) DisableTwoFactor(ctx *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := c.authService.DisableTwoFactor(ctx.Request.Context(), ctx.GetInt("user_id"), req.Code)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "two-factor authentication disabled", nil)
+}
+
+// Regenerate Recovery Codes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace every recovery code of the current user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "Two-factor code"
+// @Security Token
+// @Success 200 {object} dto.RecoveryCodesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /auth/2fa/recovery-codes [post]
+func (c *AuthController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	var req dto.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, status, err := c.authService.RegenerateRecoveryCodes(ctx.Request.Context(), ctx.GetInt("user_id"), req.Code)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "recovery codes regenerated", response)
+}
+
+// OIDC Login godoc
+// @Summary Start external identity provider login
+// @Description Get the authorization URL of the configured OIDC provider (authorization code flow with PKCE)
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.OIDCLoginResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.ErrorResponse
+// @Router /auth/oidc/login [get]
+func (c *AuthController) OIDCLogin(ctx *gin.Context) {
+	authURL, status, err := c.authService.StartOIDCLogin(ctx.Request.Context())
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "redirect to authorization url", dto.OIDCLoginResponse{AuthorizationURL: authURL})
+}
+
+// OIDC Callback godoc
+// @Summary Complete external identity provider login
+// @Description Exchange the authorization code returned by the OIDC provider for an access token
+// @Tags auth
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "Login state"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /auth/oidc/callback [get]
+func (c *AuthController) OIDCCallback(ctx *gin.Context) {
+	if errParam := ctx.Query("error"); errParam != "" {
+		utils.SendError(ctx, http.StatusUnauthorized, errParam)
+		return
+	}
+
+	code := ctx.Query("code")
+	state := ctx.Query("state")
+	if code == "" || state == "" {
+		utils.SendError(ctx, http.StatusBadRequest, "code and state are required")
+		return
+	}
+
+	response, status, err := c.authService.CompleteOIDCLogin(ctx.Request.Context(), code, state)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "login successful", response)
+}
+
+// JWKS godoc
+// @Summary Get token verification keys
+// @Description Public keys (JWKS) other services use to verify access tokens
+// @Tags auth
+// @Produce json
+// @Success 200 {object} utils.JWKS
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /.well-known/jwks.json [get]
+func (c *AuthController) JWKS(ctx *gin.Context) {
+	keyring, err := utils.LoadKeyring()
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, "signing keys unavailable")
+		return
+	}
+
+	jwks, err := keyring.JWKS()
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, jwks)
+}
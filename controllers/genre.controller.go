@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GenreController struct {
+	genreService *services.GenreService
+}
+
+func NewGenreController(genreService *services.GenreService) *GenreController {
+	return &GenreController{genreService: genreService}
+}
+
+// Get Genres godoc
+// @Summary List genres
+// @Description List genres with the number of movies in each
+// @Tags movie
+// @Produce json
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /movie/genres [get]
+func (c *GenreController) GetGenres(ctx *gin.Context) {
+	genres, err := c.genreService.GetGenres(ctx.Request.Context())
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "genres retrieved successfully", genres)
+}
+
+// Create Genre godoc
+// @Summary Add genre
+// @Description Add a new genre by admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.GenreRequest true "Genre"
+// @Security Token
+// @Success 201 {object} dto.SuccessResponse "Genre created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 409 {object} dto.ErrorResponse "Genre already exists"
+// @Router /admin/genres [post]
+func (c *GenreController) CreateGenre(ctx *gin.Context) {
+	var req dto.GenreRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	genre, status, err := c.genreService.CreateGenre(ctx.Request.Context(), ctx.GetInt("user_id"), req.Name)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Genre created successfully", genre)
+}
+
+// Rename Genre godoc
+// @Summary Rename genre
+// @Description Rename a genre by admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "Genre id"
+// @Param request body dto.GenreRequest true "Genre"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Genre renamed successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Genre not found"
+// @Failure 409 {object} dto.ErrorResponse "Genre already exists"
+// @Router /admin/genres/{id} [patch]
+func (c *GenreController) RenameGenre(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	var req dto.GenreRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	genre, status, err := c.genreService.RenameGenre(ctx.Request.Context(), ctx.GetInt("user_id"), id, req.Name)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Genre renamed successfully", genre)
+}
+
+// Merge Genre godoc
+// @Summary Merge genre
+// @Description Move every movie of a genre to another genre and delete it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "Genre id to merge"
+// @Param request body dto.MergeGenreRequest true "Target genre"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Genre merged successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Genre not found"
+// @Router /admin/genres/{id}/merge [post]
+func (c *GenreController) MergeGenre(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	var req dto.MergeGenreRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := c.genreService.MergeGenre(ctx.Request.Context(), ctx.GetInt("user_id"), id, req.TargetID)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Genre merged successfully", nil)
+}
+
+// Delete Genre godoc
+// @Summary Delete genre
+// @Description Delete a genre that is not used by any movie
+// @Tags admin
+// @Produce json
+// @Param id path integer true "Genre id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Genre deleted successfully"
+// @Failure 404 {object} dto.ErrorResponse "Genre not found"
+// @Failure 409 {object} dto.ErrorResponse "Genre is in use"
+// @Router /admin/genres/{id} [delete]
+func (c *GenreController) DeleteGenre(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid genre ID")
+		return
+	}
+
+	status, err := c.genreService.DeleteGenre(ctx.Request.Context(), ctx.GetInt("user_id"), id)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Genre deleted successfully", nil)
+}
@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"noir-backend/dto"
 	"noir-backend/services"
 	"noir-backend/utils"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -31,7 +36,9 @@ func NewMovieController(movieService *services.MovieService) *MovieController {
 // @Param release_date formData string true "Release Date (YYYY-MM-DD)"
 // @Param director_id formData int false "Director ID"
 // @Param genre_ids formData string false "Comma-separated Genre IDs (e.g., 1,2,3)"
-// @Param cast formData []string false "Cast list"
+// @Param cast formData []string false "Cast list, each entry as Name or Name:Character"
+// @Param age_rating formData string false "Age rating (SU, 13+, 17+, 21+)"
+// @Param content_advisories formData string false "Comma-separated content advisories"
 // @Param poster_path formData file false "Poster Image"
 // @Param backdrop_path formData file false "Backdrop Image"
 // @Security Token
@@ -42,17 +49,6 @@ func NewMovieController(movieService *services.MovieService) *MovieController {
 // @Failure 500 {object} dto.ErrorResponse "Something went wrong"
 // @Router /admin/movie [post]
 func (c *MovieController) AddMovie(ctx *gin.Context) {
-	role, exists := ctx.Get("role")
-	if !exists {
-		utils.SendError(ctx, http.StatusUnauthorized, "Status Unauthorized")
-		return
-	}
-
-	if role != "admin" {
-		utils.SendError(ctx, http.StatusForbidden, "only admin can access")
-		return
-	}
-
 	req, err := c.movieService.ParseCreateMovieRequest(ctx.Request.PostForm)
 	if err != nil {
 		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
@@ -78,7 +74,7 @@ func (c *MovieController) AddMovie(ctx *gin.Context) {
 		log.Println("Backdrop uploaded to:", *backdropPath)
 	}
 
-	movie, err := c.movieService.CreateMovie(ctx.Request.Context(), *req, posterPath, backdropPath)
+	movie, err := c.movieService.CreateMovie(ctx.Request.Context(), ctx.GetInt("user_id"), *req, posterPath, backdropPath)
 	if err != nil {
 		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
 	}
@@ -98,7 +94,9 @@ func (c *MovieController) AddMovie(ctx *gin.Context) {
 // @Param release_date formData string true "Release Date (YYYY-MM-DD)"
 // @Param director_id formData int false "Director ID"
 // @Param genre_ids formData string false "Comma-separated Genre IDs (e.g., 1,2,3)"
-// @Param cast formData []string false "Cast list"
+// @Param cast formData []string false "Cast list, each entry as Name or Name:Character"
+// @Param age_rating formData string false "Age rating (SU, 13+, 17+, 21+)"
+// @Param content_advisories formData string false "Comma-separated content advisories"
 // @Param poster_path formData file false "Poster Image"
 // @Param backdrop_path formData file false "Backdrop Image"
 // @Param id_movie path integer true "Movie id"
@@ -110,17 +108,6 @@ func (c *MovieController) AddMovie(ctx *gin.Context) {
 // @Failure 500 {object} dto.ErrorResponse "Something went wrong"
 // @Router /admin/movie/:id [patch]
 func (c *MovieController) UpdateMovie(ctx *gin.Context) {
-	role, exists := ctx.Get("role")
-	if !exists {
-		utils.SendError(ctx, http.StatusUnauthorized, "Status Unauthorized")
-		return
-	}
-
-	if role != "admin" {
-		utils.SendError(ctx, http.StatusForbidden, "only admin can access")
-		return
-	}
-
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
@@ -152,7 +139,7 @@ func (c *MovieController) UpdateMovie(ctx *gin.Context) {
 		log.Println("Backdrop uploaded to:", *backdropPath)
 	}
 
-	status, err := c.movieService.UpdateMovie(ctx.Request.Context(), id, *req, backdropPath, posterPath)
+	status, err := c.movieService.UpdateMovie(ctx.Request.Context(), ctx.GetInt("user_id"), id, *req, backdropPath, posterPath)
 	if err != nil {
 		utils.SendError(ctx, status, err)
 	}
@@ -162,7 +149,7 @@ func (c *MovieController) UpdateMovie(ctx *gin.Context) {
 
 // Delete Movie godoc
 // @Summary Delete existing movie
-// @Description Delete existing movie by admin
+// @Description Soft delete existing movie by admin, keeping its sales history
 // @Tags admin
 // @Produce json
 // @Param id_movie path integer true "Movie id"
@@ -171,34 +158,108 @@ func (c *MovieController) UpdateMovie(ctx *gin.Context) {
 // @Failure 400 {object} dto.ErrorResponse "Bad request"
 // @Failure 401 {object} dto.ErrorResponse "Unauthorized"
 // @Failure 403 {object} dto.ErrorResponse "Only accessed by admin"
+// @Failure 409 {object} dto.ErrorResponse "Movie has paid tickets for upcoming showtimes"
 // @Failure 500 {object} dto.ErrorResponse "Something went wrong"
 // @Router /admin/movie/:id [delete]
 func (c *MovieController) DeleteMovie(ctx *gin.Context) {
-	role, exists := ctx.Get("role")
-	if !exists {
-		utils.SendError(ctx, http.StatusUnauthorized, "Status Unauthorized")
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
 		return
 	}
 
-	if role != "admin" {
-		utils.SendError(ctx, http.StatusForbidden, "only admin can access")
+	status, err := c.movieService.DeleteMovie(ctx.Request.Context(), ctx.GetInt("user_id"), id)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
 		return
 	}
 
+	utils.SendSuccess(ctx, status, "Movie deleted successfully", nil)
+}
+
+// Restore Movie godoc
+// @Summary Restore deleted movie
+// @Description Bring a deleted movie back to the catalog
+// @Tags admin
+// @Produce json
+// @Param id path integer true "Movie id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Movie restored successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Deleted movie not found"
+// @Router /admin/movie/{id}/restore [post]
+func (c *MovieController) RestoreMovie(ctx *gin.Context) {
 	id, err := strconv.Atoi(ctx.Param("id"))
 	if err != nil {
 		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
 		return
 	}
 
-	status, err := c.movieService.DeleteMovie(ctx.Request.Context(), id)
+	status, err := c.movieService.RestoreMovie(ctx.Request.Context(), ctx.GetInt("user_id"), id)
 	if err != nil {
-		utils.SendError(ctx, status, err)
+		utils.SendError(ctx, status, err.Error())
+		return
 	}
 
-	utils.SendSuccess(ctx, status, "Movie deleted successfully", nil)
+	utils.SendSuccess(ctx, status, "Movie restored successfully", nil)
 }
 
+// Update Cast godoc
+// @Summary Replace movie cast
+// @Description Set the cast of a movie with character names and billing order
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "Movie id"
+// @Param request body dto.UpdateCastRequest true "Cast entries"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Cast updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Movie not found"
+// @Router /admin/movie/{id}/cast [put]
+func (c *MovieController) UpdateCast(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
+		return
+	}
+
+	var req dto.UpdateCastRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cast, status, err := c.movieService.ReplaceCast(ctx.Request.Context(), ctx.GetInt("user_id"), id, req.Cast)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "Cast updated successfully", cast)
+}
+
+// Get Movies godoc
+// @Summary Search movies
+// @Description List movies with full-text search, filters and sorting
+// @Tags movie
+// @Produce json
+// @Param q query string false "Search text over title, overview and cast"
+// @Param genre query string false "Genre name or id"
+// @Param director query string false "Director name"
+// @Param actor query string false "Actor name"
+// @Param release_year query int false "Release year"
+// @Param min_duration query int false "Minimum duration (in minutes)"
+// @Param max_duration query int false "Maximum duration (in minutes)"
+// @Param age_rating query string false "Comma-separated age ratings (SU, 13+, 17+, 21+)"
+// @Param without_advisory query string false "Comma-separated content advisories to exclude"
+// @Param sort query string false "relevance, newest, oldest, release_date, -release_date, title, -title, duration, -duration"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} dto.PagedMoviesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /movie [get]
 func (c *MovieController) GetMovies(ctx *gin.Context) {
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
@@ -210,7 +271,52 @@ func (c *MovieController) GetMovies(ctx *gin.Context) {
 	}
 	offset := (page - 1) * limit
 
-	movies, total, err := c.movieService.GetMovies(ctx.Request.Context(), limit, offset)
+	filter := dto.MovieFilter{
+		Query:    ctx.Query("q"),
+		Genre:    ctx.Query("genre"),
+		Director: ctx.Query("director"),
+		Actor:    ctx.Query("actor"),
+		Sort:     ctx.Query("sort"),
+	}
+
+	if filter.Sort != "" && !services.IsValidMovieSort(filter.Sort) {
+		utils.SendError(ctx, http.StatusBadRequest, "invalid sort value")
+		return
+	}
+	if filter.Sort == "relevance" && filter.Query == "" {
+		utils.SendError(ctx, http.StatusBadRequest, "sort by relevance requires a search query")
+		return
+	}
+
+	var err error
+	if filter.ReleaseYear, err = queryInt(ctx, "release_year"); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.MinDuration, err = queryInt(ctx, "min_duration"); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.MaxDuration, err = queryInt(ctx, "max_duration"); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	for _, rating := range queryList(ctx, "age_rating") {
+		if !utils.IsValidAgeRating(rating) {
+			utils.SendError(ctx, http.StatusBadRequest, "invalid age_rating value: "+rating)
+			return
+		}
+		filter.AgeRatings = append(filter.AgeRatings, rating)
+	}
+	for _, advisory := range queryList(ctx, "without_advisory") {
+		if !utils.IsValidContentAdvisory(advisory) {
+			utils.SendError(ctx, http.StatusBadRequest, "invalid without_advisory value: "+advisory)
+			return
+		}
+		filter.ExcludeAdvisories = append(filter.ExcludeAdvisories, advisory)
+	}
+
+	movies, total, err := c.movieService.GetMovies(ctx.Request.Context(), filter, limit, offset)
 	if err != nil {
 		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
 		return
@@ -291,11 +397,159 @@ func (c *MovieController) GetMovieByID(ctx *gin.Context) {
 	utils.SendSuccess(ctx, http.StatusOK, "Movie retrieved successfully", movie)
 }
 
-func (c *MovieController) GetGenres(ctx *gin.Context) {
-	genres, err := c.movieService.GetGenres(ctx.Request.Context())
+// Import Movies godoc
+// @Summary Bulk import movies
+// @Description Import movies from a CSV or JSON file. List columns in CSV are separated by "|". With dry_run the rows are only validated. Nothing is imported when a row is invalid.
+// @Tags admin
+// @Accept multipart/form-data
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param file formData file false "CSV or JSON file"
+// @Param format query string false "csv or json, guessed from the file name or content type"
+// @Param dry_run query bool false "Only validate the rows"
+// @Security Token
+// @Success 200 {object} dto.MovieImportReport "Dry run report"
+// @Success 201 {object} dto.MovieImportReport "Movies imported"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 413 {object} dto.ErrorResponse "Import file too large"
+// @Failure 422 {object} dto.MovieImportReport "Invalid rows"
+// @Router /admin/movie/import [post]
+func (c *MovieController) ImportMovies(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
 	if err != nil {
-		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		utils.SendError(ctx, http.StatusBadRequest, "invalid dry_run value")
 		return
 	}
-	utils.SendSuccess(ctx, http.StatusOK, "genres retrieved successfully", genres)
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, services.MaxMovieImportBytes)
+	body, format, err := importSource(ctx)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		utils.SendError(ctx, http.StatusRequestEntityTooLarge, fmt.Sprintf("import file is limited to %d MB", services.MaxMovieImportBytes>>20))
+		return
+	} else if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer body.Close()
+
+	report, status, err := c.movieService.ImportMovies(ctx.Request.Context(), ctx.GetInt("user_id"), body, format, dryRun)
+	if err != nil {
+		if report != nil {
+			utils.SendError(ctx, status, report)
+			return
+		}
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	message := "Movies imported successfully"
+	if dryRun {
+		message = "Import validated, nothing was imported"
+	}
+	utils.SendSuccess(ctx, status, message, report)
+}
+
+// Export Movies godoc
+// @Summary Export movies
+// @Description Stream the catalog as CSV or JSON in the bulk import format
+// @Tags admin
+// @Produce text/csv
+// @Produce json
+// @Param format query string false "csv (default) or json"
+// @Security Token
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Router /admin/movie/export [get]
+func (c *MovieController) ExportMovies(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", services.MovieTransferCSV)
+	if !services.IsValidMovieTransferFormat(format) {
+		utils.SendError(ctx, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	contentType := "text/csv"
+	if format == services.MovieTransferJSON {
+		contentType = "application/json"
+	}
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="movies.%s"`, format))
+	ctx.Status(http.StatusOK)
+
+	// The status is already sent, an error can only cut the stream short.
+	if err := c.movieService.ExportMovies(ctx.Request.Context(), format, ctx.Writer); err != nil {
+		log.Println("movie export failed:", err)
+	}
+}
+
+// Cache Stats godoc
+// @Summary Catalog cache statistics
+// @Description Hit and miss counts of the catalog cache since the server started
+// @Tags admin
+// @Produce json
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Router /admin/cache/stats [get]
+func (c *MovieController) CacheStats(ctx *gin.Context) {
+	utils.SendSuccess(ctx, http.StatusOK, "cache stats retrieved successfully", c.movieService.CacheStats())
+}
+
+func queryInt(ctx *gin.Context, key string) (*int, error) {
+	value := ctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid int value for %s", key)
+	}
+	return &i, nil
+}
+
+func queryList(ctx *gin.Context, key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(ctx.Query(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// importSource returns the uploaded "file" or, without one, the request body,
+// together with its format.
+func importSource(ctx *gin.Context) (io.ReadCloser, string, error) {
+	format := strings.ToLower(ctx.Query("format"))
+
+	header, err := ctx.FormFile("file")
+	if err == nil {
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(header.Filename)), ".")
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		return file, format, nil
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, "", err
+	}
+
+	if format == "" {
+		switch ctx.ContentType() {
+		case "text/csv":
+			format = services.MovieTransferCSV
+		case "application/json":
+			format = services.MovieTransferJSON
+		}
+	}
+	if !services.IsValidMovieTransferFormat(format) {
+		return nil, "", fmt.Errorf("upload a .csv or .json file or set format to csv or json")
+	}
+	return ctx.Request.Body, format, nil
 }
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeopleController struct {
+	peopleService *services.PeopleService
+}
+
+func NewPeopleController(peopleService *services.PeopleService) *PeopleController {
+	return &PeopleController{peopleService: peopleService}
+}
+
+// List People godoc
+// @Summary List people
+// @Description List actors and directors, optionally searching by name
+// @Tags people
+// @Produce json
+// @Param q query string false "Search by name"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} dto.PagedPeopleResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /people [get]
+func (c *PeopleController) ListPeople(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	people, total, err := c.peopleService.ListPeople(ctx.Request.Context(), ctx.Query("q"), limit, offset)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := dto.PagedPeopleResponse{
+		PageInfo: dto.NewPagination(ctx, total, page, limit),
+		Result:   people,
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "people retrieved successfully", response)
+}
+
+// Get Person godoc
+// @Summary Get person
+// @Description Get an actor or director with their filmography
+// @Tags people
+// @Produce json
+// @Param id path integer true "Person id"
+// @Success 200 {object} dto.PersonResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /people/{id} [get]
+func (c *PeopleController) GetPerson(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid person ID")
+		return
+	}
+
+	person, status, err := c.peopleService.GetPerson(ctx.Request.Context(), id)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "person retrieved successfully", person)
+}
+
+// Create Person godoc
+// @Summary Add person
+// @Description Add an actor or director by admin
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param first_name formData string true "First name"
+// @Param last_name formData string false "Last name"
+// @Param bio formData string false "Biography"
+// @Param birth_date formData string false "Birth date (YYYY-MM-DD)"
+// @Param photo formData file false "Photo"
+// @Security Token
+// @Success 201 {object} dto.SuccessResponse "Person created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Router /admin/people [post]
+func (c *PeopleController) CreatePerson(ctx *gin.Context) {
+	var req dto.CreatePersonRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	photoPath, err := utils.SaveUploadedFile(ctx, "photo", "uploads/people")
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	person, err := c.peopleService.CreatePerson(ctx.Request.Context(), ctx.GetInt("user_id"), req, photoPath)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusCreated, "Person created successfully", person)
+}
+
+// Update Person godoc
+// @Summary Update person
+// @Description Update an actor or director by admin
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path integer true "Person id"
+// @Param first_name formData string false "First name"
+// @Param last_name formData string false "Last name"
+// @Param bio formData string false "Biography"
+// @Param birth_date formData string false "Birth date (YYYY-MM-DD)"
+// @Param photo formData file false "Photo"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Person updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Person not found"
+// @Router /admin/people/{id} [patch]
+func (c *PeopleController) UpdatePerson(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid person ID")
+		return
+	}
+
+	var req dto.UpdatePersonRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	photoPath, err := utils.SaveUploadedFile(ctx, "photo", "uploads/people")
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	person, status, err := c.peopleService.UpdatePerson(ctx.Request.Context(), ctx.GetInt("user_id"), id, req, photoPath)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "Person updated successfully", person)
+}
+
+// Delete Person godoc
+// @Summary Delete person
+// @Description Delete an actor or director and their cast entries by admin
+// @Tags admin
+// @Produce json
+// @Param id path integer true "Person id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Person deleted successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Person not found"
+// @Router /admin/people/{id} [delete]
+func (c *PeopleController) DeletePerson(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid person ID")
+		return
+	}
+
+	status, err := c.peopleService.DeletePerson(ctx.Request.Context(), ctx.GetInt("user_id"), id)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "Person deleted successfully", nil)
+}
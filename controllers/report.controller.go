@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/middleware"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultReportDays is the period covered when no dates are given.
+const defaultReportDays = 30
+
+type ReportController struct {
+	reportService *services.ReportService
+}
+
+func NewReportController(reportService *services.ReportService) *ReportController {
+	return &ReportController{reportService: reportService}
+}
+
+// Sales Report godoc
+// @Summary Sales and occupancy report
+// @Description Revenue, tickets sold, occupancy and cancellation rate of the showtimes screened in a period, grouped by movie, cinema, showtime, day or payment method. Cinema managers only see their cinemas.
+// @Tags admin
+// @Produce json
+// @Produce text/csv
+// @Param group_by query string false "movie (default), cinema, showtime, day or payment_method"
+// @Param from query string false "First day, YYYY-MM-DD (default 30 days ago)"
+// @Param to query string false "Last day, YYYY-MM-DD (default today)"
+// @Param movie_id query int false "Filter by movie"
+// @Param cinema_id query int false "Filter by cinema"
+// @Param format query string false "json (default) or csv"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Report generated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Something went wrong"
+// @Router /admin/reports/sales [get]
+func (c *ReportController) SalesReport(ctx *gin.Context) {
+	filter := dto.SalesReportFilter{
+		GroupBy:   ctx.DefaultQuery("group_by", services.ReportByMovie),
+		CinemaIDs: middleware.ManagedCinemaIDs(ctx),
+	}
+	if !services.IsValidReportGroup(filter.GroupBy) {
+		utils.SendError(ctx, http.StatusBadRequest, "group_by must be movie, cinema, showtime, day or payment_method")
+		return
+	}
+
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		utils.SendError(ctx, http.StatusBadRequest, "format must be json or csv")
+		return
+	}
+
+	today := time.Now().Format("2006-01-02")
+	from, err := time.Parse("2006-01-02", ctx.DefaultQuery("from", time.Now().AddDate(0, 0, -defaultReportDays+1).Format("2006-01-02")))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "from must be formatted as YYYY-MM-DD")
+		return
+	}
+	to, err := time.Parse("2006-01-02", ctx.DefaultQuery("to", today))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "to must be formatted as YYYY-MM-DD")
+		return
+	}
+	if to.Before(from) {
+		utils.SendError(ctx, http.StatusBadRequest, "to must not be before from")
+		return
+	}
+	filter.From, filter.To = from, to.AddDate(0, 0, 1)
+
+	if movieID := ctx.Query("movie_id"); movieID != "" {
+		if filter.MovieID, err = strconv.Atoi(movieID); err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
+			return
+		}
+	}
+	if cinemaID := ctx.Query("cinema_id"); cinemaID != "" {
+		if filter.CinemaID, err = strconv.Atoi(cinemaID); err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "Invalid cinema ID")
+			return
+		}
+		if !middleware.CanAccessCinema(ctx, filter.CinemaID) {
+			utils.SendError(ctx, http.StatusForbidden, "you do not manage this cinema")
+			return
+		}
+	}
+
+	report, err := c.reportService.SalesReport(ctx.Request.Context(), filter)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format == "csv" {
+		ctx.Header("Content-Type", "text/csv")
+		ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="sales-by-%s-%s-%s.csv"`, report.GroupBy, report.From, report.To))
+		ctx.Status(http.StatusOK)
+		if err := services.WriteSalesReportCSV(ctx.Writer, report); err != nil {
+			log.Println("sales report export failed:", err)
+		}
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "Report generated successfully", report)
+}
+
+// Dashboard godoc
+// @Summary Back-office dashboard
+// @Description Today's revenue and tickets sold, new registrations, upcoming showtimes with the lowest and highest fill and pending transactions about to expire. Cached for a few seconds.
+// @Tags admin
+// @Produce json
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Dashboard retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Something went wrong"
+// @Router /admin/reports/dashboard [get]
+func (c *ReportController) Dashboard(ctx *gin.Context) {
+	summary, err := c.reportService.Dashboard(ctx.Request.Context(), middleware.ManagedCinemaIDs(ctx))
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "Dashboard retrieved successfully", summary)
+}
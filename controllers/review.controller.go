@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewController struct {
+	reviewService *services.ReviewService
+}
+
+func NewReviewController(reviewService *services.ReviewService) *ReviewController {
+	return &ReviewController{reviewService: reviewService}
+}
+
+// List Movie Reviews godoc
+// @Summary List movie reviews
+// @Description List published reviews of a movie
+// @Tags movie
+// @Produce json
+// @Param id path integer true "Movie id"
+// @Param sort query string false "newest, oldest, highest, lowest"
+// @Param verified query bool false "Only reviews of verified viewers"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} dto.PagedReviewsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /movie/{id}/reviews [get]
+func (c *ReviewController) ListMovieReviews(ctx *gin.Context) {
+	movieID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
+		return
+	}
+
+	filter := dto.ReviewFilter{
+		MovieID:      &movieID,
+		Status:       "published",
+		VerifiedOnly: ctx.Query("verified") == "true",
+		Sort:         ctx.DefaultQuery("sort", "newest"),
+	}
+	c.listReviews(ctx, filter)
+}
+
+// List Reviews godoc
+// @Summary List reviews for moderation
+// @Description List reviews of every movie by admin
+// @Tags admin
+// @Produce json
+// @Param movie_id query int false "Movie id"
+// @Param status query string false "published, hidden"
+// @Param sort query string false "newest, oldest, highest, lowest"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Security Token
+// @Success 200 {object} dto.PagedReviewsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /admin/reviews [get]
+func (c *ReviewController) ListReviews(ctx *gin.Context) {
+	movieID, err := queryInt(ctx, "movie_id")
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter := dto.ReviewFilter{
+		MovieID: movieID,
+		Status:  ctx.Query("status"),
+		Sort:    ctx.DefaultQuery("sort", "newest"),
+	}
+	if filter.Status != "" && filter.Status != "published" && filter.Status != "hidden" {
+		utils.SendError(ctx, http.StatusBadRequest, "invalid status")
+		return
+	}
+	c.listReviews(ctx, filter)
+}
+
+func (c *ReviewController) listReviews(ctx *gin.Context, filter dto.ReviewFilter) {
+	if !services.IsValidReviewSort(filter.Sort) {
+		utils.SendError(ctx, http.StatusBadRequest, "invalid sort")
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	reviews, total, err := c.reviewService.ListReviews(ctx.Request.Context(), filter, limit, offset)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := dto.PagedReviewsResponse{
+		PageInfo: dto.NewPagination(ctx, total, page, limit),
+		Result:   reviews,
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "reviews retrieved successfully", response)
+}
+
+// Save Review godoc
+// @Summary Review movie
+// @Description Create or replace the logged in user's review of a movie
+// @Tags movie
+// @Accept json
+// @Produce json
+// @Param id path integer true "Movie id"
+// @Param request body dto.ReviewRequest true "Review"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Review saved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 404 {object} dto.ErrorResponse "Movie not found"
+// @Router /movie/{id}/reviews [post]
+func (c *ReviewController) SaveReview(ctx *gin.Context) {
+	movieID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
+		return
+	}
+
+	var req dto.ReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	review, status, err := c.reviewService.SaveReview(ctx.Request.Context(), ctx.GetInt("user_id"), movieID, req)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Review saved successfully", review)
+}
+
+// Delete Own Review godoc
+// @Summary Delete own review
+// @Description Delete the logged in user's review of a movie
+// @Tags movie
+// @Produce json
+// @Param id path integer true "Movie id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Review deleted successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 404 {object} dto.ErrorResponse "Review not found"
+// @Router /movie/{id}/reviews [delete]
+func (c *ReviewController) DeleteOwnReview(ctx *gin.Context) {
+	movieID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
+		return
+	}
+
+	status, err := c.reviewService.DeleteOwnReview(ctx.Request.Context(), ctx.GetInt("user_id"), movieID)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Review deleted successfully", nil)
+}
+
+// Moderate Review godoc
+// @Summary Moderate review
+// @Description Hide or publish a review by admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "Review id"
+// @Param request body dto.ModerateReviewRequest true "Moderation"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Review moderated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Review not found"
+// @Router /admin/reviews/{id} [patch]
+func (c *ReviewController) ModerateReview(ctx *gin.Context) {
+	reviewID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid review ID")
+		return
+	}
+
+	var req dto.ModerateReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	review, status, err := c.reviewService.ModerateReview(ctx.Request.Context(), ctx.GetInt("user_id"), reviewID, req)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Review moderated successfully", review)
+}
+
+// Delete Review godoc
+// @Summary Delete review
+// @Description Delete any review by admin
+// @Tags admin
+// @Produce json
+// @Param id path integer true "Review id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Review deleted successfully"
+// @Failure 404 {object} dto.ErrorResponse "Review not found"
+// @Router /admin/reviews/{id} [delete]
+func (c *ReviewController) DeleteReview(ctx *gin.Context) {
+	reviewID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid review ID")
+		return
+	}
+
+	status, err := c.reviewService.DeleteReview(ctx.Request.Context(), ctx.GetInt("user_id"), reviewID)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Review deleted successfully", nil)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchController struct {
+	searchService *services.SearchService
+}
+
+func NewSearchController(searchService *services.SearchService) *SearchController {
+	return &SearchController{searchService: searchService}
+}
+
+// Suggest godoc
+// @Summary Typeahead suggestions
+// @Description Suggest movies, actors and directors matching the typed text
+// @Tags search
+// @Produce json
+// @Param q query string true "Typed text (min 2 characters)"
+// @Param limit query int false "Limit (max 20)"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /search/suggest [get]
+func (c *SearchController) Suggest(ctx *gin.Context) {
+	q := strings.TrimSpace(ctx.Query("q"))
+	if len([]rune(q)) < 2 {
+		utils.SendError(ctx, http.StatusBadRequest, "q must be at least 2 characters")
+		return
+	}
+
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 20 {
+		limit = 20
+	}
+
+	suggestions, err := c.searchService.Suggest(ctx.Request.Context(), q, limit)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "suggestions retrieved successfully", suggestions)
+}
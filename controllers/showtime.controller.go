@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/middleware"
+	"noir-backend/services"
+	"noir-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShowtimeController struct {
+	showtimeService *services.ShowtimeService
+}
+
+func NewShowtimeController(showtimeService *services.ShowtimeService) *ShowtimeController {
+	return &ShowtimeController{showtimeService: showtimeService}
+}
+
+// Generate Showtimes godoc
+// @Summary Generate showtimes from a weekly template
+// @Description Create the showtimes of a movie in a cinema for every weekday time between two dates. Past and overlapping slots are skipped and reported. With dry_run nothing is saved.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.ShowtimeTemplateRequest true "Weekly template"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Dry run report"
+// @Success 201 {object} dto.SuccessResponse "Showtimes generated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 404 {object} dto.ErrorResponse "Movie or cinema not found"
+// @Failure 409 {object} dto.ErrorResponse "Cinema is not active"
+// @Router /admin/showtimes/generate [post]
+func (c *ShowtimeController) GenerateShowtimes(ctx *gin.Context) {
+	var req dto.ShowtimeTemplateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !middleware.CanAccessCinema(ctx, req.CinemaID) {
+		utils.SendError(ctx, http.StatusForbidden, "you do not manage this cinema")
+		return
+	}
+
+	report, status, err := c.showtimeService.GenerateShowtimes(ctx.Request.Context(), ctx.GetInt("user_id"), req)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	message := "Showtimes generated successfully"
+	if req.DryRun {
+		message = "Showtime template is valid"
+	}
+	utils.SendSuccess(ctx, status, message, report)
+}
@@ -3,8 +3,11 @@ package controllers
 import (
 	"net/http"
 	"noir-backend/dto"
+	"noir-backend/middleware"
 	"noir-backend/services"
 	"noir-backend/utils"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -70,3 +73,280 @@ func (c *TransactionController) GetTransaction(ctx *gin.Context) {
 
 	utils.SendSuccess(ctx, http.StatusOK, "Transaction retrieved successfully", response)
 }
+
+// Search Transactions godoc
+// @Summary Search transactions
+// @Description Search bookings by recipient email or phone, code prefix, status, showtime and booking date. Cinema managers only see their cinemas.
+// @Tags admin
+// @Produce json
+// @Param email query string false "Recipient email, partial match"
+// @Param phone query string false "Recipient phone number, partial match"
+// @Param code query string false "Transaction code prefix"
+// @Param status query string false "pending, paid, cancelled or expired"
+// @Param showtime_id query int false "Showtime"
+// @Param from query string false "Booked on or after, YYYY-MM-DD"
+// @Param to query string false "Booked on or before, YYYY-MM-DD"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Transactions retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Something went wrong"
+// @Router /admin/transactions [get]
+func (c *TransactionController) SearchTransactions(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	filter := dto.TransactionFilter{
+		Email:      ctx.Query("email"),
+		Phone:      ctx.Query("phone"),
+		CodePrefix: ctx.Query("code"),
+		Status:     ctx.Query("status"),
+		CinemaIDs:  middleware.ManagedCinemaIDs(ctx),
+	}
+	if filter.Status != "" && !services.IsValidTransactionStatus(filter.Status) {
+		utils.SendError(ctx, http.StatusBadRequest, "status must be pending, paid, cancelled or expired")
+		return
+	}
+	if showtimeID := ctx.Query("showtime_id"); showtimeID != "" {
+		id, err := strconv.Atoi(showtimeID)
+		if err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "Invalid showtime ID")
+			return
+		}
+		filter.ShowtimeID = id
+	}
+	if from := ctx.Query("from"); from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "from must be formatted as YYYY-MM-DD")
+			return
+		}
+		filter.From = &date
+	}
+	if to := ctx.Query("to"); to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			utils.SendError(ctx, http.StatusBadRequest, "to must be formatted as YYYY-MM-DD")
+			return
+		}
+		date = date.AddDate(0, 0, 1)
+		filter.To = &date
+	}
+
+	transactions, total, err := c.transactionService.SearchTransactions(ctx.Request.Context(), filter, limit, offset)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pagination := dto.NewPagination(ctx, total, page, limit)
+	response := dto.PagedTransactionsResponse{
+		PageInfo: pagination,
+		Result:   transactions,
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "Transactions retrieved successfully", response)
+}
+
+// Get Transaction Detail godoc
+// @Summary Get transaction detail
+// @Description Get a transaction with its buyer, showtime, tickets and payment events
+// @Tags admin
+// @Produce json
+// @Param code path string true "Transaction code"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Transaction retrieved successfully"
+// @Failure 404 {object} dto.ErrorResponse "Transaction not found"
+// @Router /admin/transactions/{code} [get]
+func (c *TransactionController) GetTransactionDetail(ctx *gin.Context) {
+	detail, status, err := c.transactionService.GetTransactionDetail(ctx.Request.Context(), ctx.Param("code"), middleware.ManagedCinemaIDs(ctx))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Transaction retrieved successfully", detail)
+}
+
+// Cancel Transaction godoc
+// @Summary Cancel transaction
+// @Description Cancel a pending or paid transaction and release its seats. Refunds are handled outside the system.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param code path string true "Transaction code"
+// @Param request body dto.TransactionActionRequest true "Reason"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Transaction cancelled successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Transaction not found"
+// @Failure 409 {object} dto.ErrorResponse "Transaction already cancelled"
+// @Router /admin/transactions/{code}/cancel [post]
+func (c *TransactionController) CancelTransaction(ctx *gin.Context) {
+	var req dto.TransactionActionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, status, err := c.transactionService.CancelTransactionByStaff(ctx.Request.Context(),
+		ctx.GetInt("user_id"), ctx.Param("code"), req.Reason, middleware.ManagedCinemaIDs(ctx))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Transaction cancelled successfully", result)
+}
+
+// Mark Transaction Paid godoc
+// @Summary Mark transaction paid
+// @Description Record a payment received outside the payment flow for a pending transaction
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param code path string true "Transaction code"
+// @Param request body dto.TransactionActionRequest true "Reason"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Transaction marked paid"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Transaction not found"
+// @Failure 409 {object} dto.ErrorResponse "Transaction is not pending"
+// @Router /admin/transactions/{code}/mark-paid [post]
+func (c *TransactionController) MarkTransactionPaid(ctx *gin.Context) {
+	var req dto.TransactionActionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	transaction, status, err := c.transactionService.MarkTransactionPaid(ctx.Request.Context(),
+		ctx.GetInt("user_id"), ctx.Param("code"), req.Reason, middleware.ManagedCinemaIDs(ctx))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Transaction marked paid", transaction)
+}
+
+// Resend Confirmation godoc
+// @Summary Resend booking confirmation
+// @Description Email the booking confirmation of a paid transaction to its recipient again
+// @Tags admin
+// @Produce json
+// @Param code path string true "Transaction code"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Confirmation sent"
+// @Failure 404 {object} dto.ErrorResponse "Transaction not found"
+// @Failure 409 {object} dto.ErrorResponse "Transaction is not paid"
+// @Failure 502 {object} dto.ErrorResponse "Email could not be sent"
+// @Router /admin/transactions/{code}/resend-confirmation [post]
+func (c *TransactionController) ResendConfirmation(ctx *gin.Context) {
+	status, err := c.transactionService.ResendConfirmation(ctx.Request.Context(),
+		ctx.GetInt("user_id"), ctx.Param("code"), middleware.ManagedCinemaIDs(ctx))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Confirmation sent", nil)
+}
+
+// Create Group Booking godoc
+// @Summary Create group booking
+// @Description Reserve seats for a group and invite others by email to pay their share. Seats not assigned to an invitee are the organiser's share. The seats are released unless every share is paid before the booking expires.
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateGroupBookingRequest true "Booking and shares"
+// @Security Token
+// @Success 201 {object} dto.SuccessResponse "Group booking created successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Showtime not found"
+// @Router /transaction/group [post]
+func (c *TransactionController) CreateGroupBooking(ctx *gin.Context) {
+	var req dto.CreateGroupBookingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, status, err := c.transactionService.CreateGroupBooking(ctx.Request.Context(), req, ctx.GetInt("user_id"))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Group booking created successfully", response)
+}
+
+// Get Group Booking godoc
+// @Summary Get group booking
+// @Description Get the organiser's group booking with the payment state of every share
+// @Tags transaction
+// @Produce json
+// @Param code path string true "Transaction code"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Group booking retrieved successfully"
+// @Failure 404 {object} dto.ErrorResponse "Group booking not found"
+// @Router /transaction/group/{code} [get]
+func (c *TransactionController) GetGroupBooking(ctx *gin.Context) {
+	response, status, err := c.transactionService.GetGroupBooking(ctx.Request.Context(), ctx.Param("code"), ctx.GetInt("user_id"))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Group booking retrieved successfully", response)
+}
+
+// Get Group Share godoc
+// @Summary Get group booking share
+// @Description Get the share an invitee was asked to pay, with the movie, showtime and payment deadline
+// @Tags transaction
+// @Produce json
+// @Param code path string true "Share code"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Share retrieved successfully"
+// @Failure 404 {object} dto.ErrorResponse "Share not found"
+// @Router /transaction/group/shares/{code} [get]
+func (c *TransactionController) GetGroupShare(ctx *gin.Context) {
+	response, status, err := c.transactionService.GetGroupShare(ctx.Request.Context(), ctx.Param("code"))
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Share retrieved successfully", response)
+}
+
+// Pay Group Share godoc
+// @Summary Pay group booking share
+// @Description Pay one share of a group booking. The booking is paid once its last share is.
+// @Tags transaction
+// @Accept json
+// @Produce json
+// @Param code path string true "Share code"
+// @Param request body dto.PayGroupShareRequest true "Payment"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Share paid successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Share not found"
+// @Failure 409 {object} dto.ErrorResponse "Share already paid or group booking no longer pending"
+// @Router /transaction/group/shares/{code}/pay [post]
+func (c *TransactionController) PayGroupShare(ctx *gin.Context) {
+	var req dto.PayGroupShareRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, status, err := c.transactionService.PayGroupShare(ctx.Request.Context(), ctx.GetInt("user_id"), ctx.Param("code"), req)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+	utils.SendSuccess(ctx, status, "Share paid successfully", response)
+}
@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserController struct {
+	userService        *services.UserService
+	transactionService *services.TransactionService
+}
+
+func NewUserController(userService *services.UserService, transactionService *services.TransactionService) *UserController {
+	return &UserController{userService: userService, transactionService: transactionService}
+}
+
+// List Users godoc
+// @Summary List users
+// @Description List and search users by admin
+// @Tags admin
+// @Produce json
+// @Param q query string false "Search by email, name or phone number"
+// @Param role query string false "Filter by role"
+// @Param status query string false "Filter by status (active, disabled)"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Users retrieved successfully"
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Failure 403 {object} dto.ErrorResponse "Forbidden"
+// @Failure 500 {object} dto.ErrorResponse "Something went wrong"
+// @Router /admin/users [get]
+func (c *UserController) ListUsers(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	filter := dto.UserFilter{
+		Search: ctx.Query("q"),
+		Role:   ctx.Query("role"),
+		Status: ctx.Query("status"),
+	}
+
+	users, total, err := c.userService.ListUsers(ctx.Request.Context(), filter, limit, offset)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	pagination := dto.NewPagination(ctx, total, page, limit)
+	response := dto.PagedUsersResponse{
+		PageInfo: pagination,
+		Result:   users,
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "users retrieved successfully", response)
+}
+
+// Get User godoc
+// @Summary Get user detail
+// @Description Get user profile and booking history by admin
+// @Tags admin
+// @Produce json
+// @Param id path integer true "User id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "User retrieved successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /admin/users/{id} [get]
+func (c *UserController) GetUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := c.userService.GetUser(ctx.Request.Context(), id)
+	if err != nil {
+		utils.SendError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	cinemaIDs, err := c.userService.GetUserCinemaIDs(ctx.Request.Context(), id)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	history, err := c.transactionService.GetUserTransactions(ctx.Request.Context(), id)
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := dto.UserDetailResponse{
+		User:           *user,
+		CinemaIDs:      cinemaIDs,
+		BookingHistory: history,
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "user retrieved successfully", response)
+}
+
+// Update User Role godoc
+// @Summary Change user role
+// @Description Change user role by admin, cinema_ids is required for cinema managers
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User id"
+// @Param request body dto.UpdateRoleRequest true "Role request"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "User role updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /admin/users/{id}/role [patch]
+func (c *UserController) UpdateRole(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req dto.UpdateRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := c.userService.UpdateRole(ctx.Request.Context(), ctx.GetInt("user_id"), id, req)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "user role updated successfully", nil)
+}
+
+// Update User Status godoc
+// @Summary Disable or enable user
+// @Description Disable or enable user account by admin
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path integer true "User id"
+// @Param request body dto.UpdateUserStatusRequest true "Status request"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "User status updated successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /admin/users/{id}/status [patch]
+func (c *UserController) UpdateStatus(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	var req dto.UpdateUserStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := c.userService.SetActive(ctx.Request.Context(), ctx.GetInt("user_id"), id, *req.IsActive)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "user status updated successfully", nil)
+}
+
+// Force Logout godoc
+// @Summary Force logout user
+// @Description Revoke every active session of a user by admin
+// @Tags admin
+// @Produce json
+// @Param id path integer true "User id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "User logged out successfully"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "User not found"
+// @Router /admin/users/{id}/logout [post]
+func (c *UserController) ForceLogout(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	status, err := c.userService.ForceLogout(ctx.Request.Context(), ctx.GetInt("user_id"), id)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "user logged out successfully", nil)
+}
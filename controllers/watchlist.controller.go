@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/services"
+	"noir-backend/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistController struct {
+	watchlistService *services.WatchlistService
+}
+
+func NewWatchlistController(watchlistService *services.WatchlistService) *WatchlistController {
+	return &WatchlistController{watchlistService: watchlistService}
+}
+
+// Get Watchlist godoc
+// @Summary Get watchlist
+// @Description List the movies on the logged in user's watchlist
+// @Tags profile
+// @Produce json
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse "Unauthorized"
+// @Router /profile/watchlist [get]
+func (c *WatchlistController) GetWatchlist(ctx *gin.Context) {
+	items, err := c.watchlistService.GetWatchlist(ctx.Request.Context(), ctx.GetInt("user_id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, http.StatusOK, "watchlist retrieved successfully", items)
+}
+
+// Add To Watchlist godoc
+// @Summary Add to watchlist
+// @Description Save a movie and get reminded when tickets go on sale and on release day
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body dto.WatchlistRequest true "Movie"
+// @Security Token
+// @Success 201 {object} dto.SuccessResponse "Movie added to watchlist"
+// @Failure 400 {object} dto.ErrorResponse "Bad request"
+// @Failure 404 {object} dto.ErrorResponse "Movie not found"
+// @Router /profile/watchlist [post]
+func (c *WatchlistController) AddToWatchlist(ctx *gin.Context) {
+	var req dto.WatchlistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := c.watchlistService.AddToWatchlist(ctx.Request.Context(), ctx.GetInt("user_id"), req.MovieID)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Movie added to watchlist", nil)
+}
+
+// Remove From Watchlist godoc
+// @Summary Remove from watchlist
+// @Description Remove a movie from the logged in user's watchlist
+// @Tags profile
+// @Produce json
+// @Param movie_id path integer true "Movie id"
+// @Security Token
+// @Success 200 {object} dto.SuccessResponse "Movie removed from watchlist"
+// @Failure 404 {object} dto.ErrorResponse "Movie is not on the watchlist"
+// @Router /profile/watchlist/{movie_id} [delete]
+func (c *WatchlistController) RemoveFromWatchlist(ctx *gin.Context) {
+	movieID, err := strconv.Atoi(ctx.Param("movie_id"))
+	if err != nil {
+		utils.SendError(ctx, http.StatusBadRequest, "Invalid movie ID")
+		return
+	}
+
+	status, err := c.watchlistService.RemoveFromWatchlist(ctx.Request.Context(), ctx.GetInt("user_id"), movieID)
+	if err != nil {
+		utils.SendError(ctx, status, err.Error())
+		return
+	}
+
+	utils.SendSuccess(ctx, status, "Movie removed from watchlist", nil)
+}
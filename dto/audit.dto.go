@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"noir-backend/models"
+	"time"
+)
+
+type AuditLogFilter struct {
+	ActorID    int
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+type PagedAuditLogsResponse struct {
+	PageInfo Pagination        `json:"page_info"`
+	Result   []models.AuditLog `json:"audit_logs"`
+}
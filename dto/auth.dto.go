@@ -25,9 +25,38 @@ type UserResponse struct {
 	LastLogin *time.Time `json:"last_login,omitempty"`
 }
 
+type UpdateProfileRequest struct {
+	FirstName   *string `json:"first_name"`
+	LastName    *string `json:"last_name"`
+	PhoneNumber *string `json:"phone_number"`
+	BirthDate   *string `json:"birth_date"`
+}
+
 type AuthResponse struct {
-	User  *UserResponse `json:"user"`
-	Token string        `json:"token"`
+	User                   *UserResponse `json:"user,omitempty"`
+	Token                  string        `json:"token,omitempty"`
+	RequiresTwoFactor      bool          `json:"requires_two_factor,omitempty"`
+	ChallengeToken         string        `json:"challenge_token,omitempty"`
+	TwoFactorSetupRequired bool          `json:"two_factor_setup_required,omitempty"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 type PasswordResetRequest struct {
@@ -37,3 +66,7 @@ type PasswordResetRequest struct {
 type ResetPasswordRequest struct {
 	NewPassword string `form:"new_password" json:"new_password" binding:"required,min=6"`
 }
+
+type OIDCLoginResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
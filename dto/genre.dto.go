@@ -0,0 +1,15 @@
+package dto
+
+type GenreRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+type MergeGenreRequest struct {
+	TargetID int `json:"target_id" binding:"required"`
+}
+
+type GenreResponse struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	MovieCount int    `json:"movie_count"`
+}
@@ -3,41 +3,64 @@ package dto
 import "time"
 
 type CreateMovieRequest struct {
-	Title       string    `json:"title" binding:"required"`
-	Overview    string    `json:"overview" binding:"required"`
-	Duration    int       `json:"duration" binding:"required,min=1"`
-	ReleaseDate time.Time `json:"release_date" binding:"required"`
-	Director    string    `json:"director_id"`
-	GenreIDs    []int     `json:"genres_ids"`
-	Cast        []string  `json:"cast"`
+	Title             string    `json:"title" binding:"required"`
+	Overview          string    `json:"overview" binding:"required"`
+	Duration          int       `json:"duration" binding:"required,min=1"`
+	ReleaseDate       time.Time `json:"release_date" binding:"required"`
+	Director          string    `json:"director_id"`
+	GenreIDs          []int     `json:"genres_ids"`
+	Cast              []string  `json:"cast"`
+	AgeRating         string    `json:"age_rating"`
+	ContentAdvisories []string  `json:"content_advisories"`
 }
 
 type UpdateMovieRequest struct {
-	Title       *string    `json:"title"`
-	Overview    *string    `json:"overview"`
-	Duration    *int       `json:"duration"`
-	ReleaseDate *time.Time `json:"release_date"`
-	Director    *string    `json:"director"`
-	GenreIDs    *[]int     `json:"genre_ids"`
-	Cast        *[]string  `json:"cast"`
+	Title             *string    `json:"title"`
+	Overview          *string    `json:"overview"`
+	Duration          *int       `json:"duration"`
+	ReleaseDate       *time.Time `json:"release_date"`
+	Director          *string    `json:"director"`
+	GenreIDs          *[]int     `json:"genre_ids"`
+	Cast              *[]string  `json:"cast"`
+	AgeRating         *string    `json:"age_rating"`
+	ContentAdvisories *[]string  `json:"content_advisories"`
 }
 
 type MovieResponse struct {
-	MovieID      int       `json:"movie_id"`
-	Title        string    `json:"title"`
-	PosterPath   *string   `json:"poster_path"`
-	BackdropPath *string   `json:"backdrop_path"`
-	Overview     string    `json:"overview"`
-	Duration     int       `json:"duration"`
-	ReleaseDate  time.Time `json:"release_date"`
-	Director     string    `json:"director"`
-	Genre        []string  `json:"genre"`
-	Cast         []string  `json:"cast"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	MovieID           int          `json:"movie_id"`
+	Title             string       `json:"title"`
+	PosterPath        *string      `json:"poster_path"`
+	BackdropPath      *string      `json:"backdrop_path"`
+	Overview          string       `json:"overview"`
+	Duration          int          `json:"duration"`
+	ReleaseDate       time.Time    `json:"release_date"`
+	AgeRating         string       `json:"age_rating"`
+	ContentAdvisories []string     `json:"content_advisories"`
+	Director          string       `json:"director"`
+	Genre             []string     `json:"genre"`
+	Cast              []string     `json:"cast"`
+	CastMembers       []CastMember `json:"cast_members,omitempty"`
+	Rating            *float64     `json:"rating"`
+	RatingCount       int          `json:"rating_count"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
 }
 
 type PagedMoviesResponse struct {
 	PageInfo Pagination      `json:"page_info"`
 	Result   []MovieResponse `json:"movies"`
-}
\ No newline at end of file
+}
+type MovieFilter struct {
+	Query       string
+	Genre       string
+	Director    string
+	Actor       string
+	ReleaseYear *int
+	MinDuration *int
+	MaxDuration *int
+	// AgeRatings keeps movies with one of the listed ratings.
+	AgeRatings []string
+	// ExcludeAdvisories drops movies tagged with any of the listed advisories.
+	ExcludeAdvisories []string
+	Sort              string
+}
@@ -0,0 +1,31 @@
+package dto
+
+// MovieTransferRow is one movie of a bulk import or export file. In CSV the
+// list columns hold values separated by "|", cast entries as Name:Character.
+type MovieTransferRow struct {
+	Title             string   `json:"title"`
+	Overview          string   `json:"overview"`
+	Duration          int      `json:"duration"`
+	ReleaseDate       string   `json:"release_date"`
+	Director          string   `json:"director"`
+	Genres            []string `json:"genres"`
+	Cast              []string `json:"cast"`
+	AgeRating         string   `json:"age_rating"`
+	ContentAdvisories []string `json:"content_advisories"`
+	PosterPath        *string  `json:"poster_path"`
+	BackdropPath      *string  `json:"backdrop_path"`
+}
+
+type MovieImportRowError struct {
+	Row    int      `json:"row"`
+	Title  string   `json:"title,omitempty"`
+	Errors []string `json:"errors"`
+}
+
+type MovieImportReport struct {
+	DryRun   bool                  `json:"dry_run"`
+	Total    int                   `json:"total"`
+	Valid    int                   `json:"valid"`
+	Imported int                   `json:"imported"`
+	Errors   []MovieImportRowError `json:"errors"`
+}
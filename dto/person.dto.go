@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+type CreatePersonRequest struct {
+	FirstName string     `form:"first_name" binding:"required"`
+	LastName  string     `form:"last_name"`
+	Bio       *string    `form:"bio"`
+	BirthDate *time.Time `form:"birth_date" time_format:"2006-01-02"`
+}
+
+type UpdatePersonRequest struct {
+	FirstName *string    `form:"first_name"`
+	LastName  *string    `form:"last_name"`
+	Bio       *string    `form:"bio"`
+	BirthDate *time.Time `form:"birth_date" time_format:"2006-01-02"`
+}
+
+type PersonListItem struct {
+	ID        int     `json:"id"`
+	Name      string  `json:"name"`
+	PhotoPath *string `json:"photo_path"`
+}
+
+type PagedPeopleResponse struct {
+	PageInfo Pagination       `json:"page_info"`
+	Result   []PersonListItem `json:"people"`
+}
+
+type ActingCredit struct {
+	MovieID       int       `json:"movie_id"`
+	Title         string    `json:"title"`
+	PosterPath    *string   `json:"poster_path"`
+	ReleaseDate   time.Time `json:"release_date"`
+	CharacterName *string   `json:"character_name"`
+	BillingOrder  *int      `json:"billing_order"`
+}
+
+type DirectingCredit struct {
+	MovieID     int       `json:"movie_id"`
+	Title       string    `json:"title"`
+	PosterPath  *string   `json:"poster_path"`
+	ReleaseDate time.Time `json:"release_date"`
+}
+
+type Filmography struct {
+	Acting    []ActingCredit    `json:"acting"`
+	Directing []DirectingCredit `json:"directing"`
+}
+
+type PersonResponse struct {
+	ID          int         `json:"id"`
+	FirstName   string      `json:"first_name"`
+	LastName    string      `json:"last_name"`
+	Bio         *string     `json:"bio"`
+	PhotoPath   *string     `json:"photo_path"`
+	BirthDate   *time.Time  `json:"birth_date"`
+	Filmography Filmography `json:"filmography"`
+}
+
+type CastEntryRequest struct {
+	PersonID      int     `json:"person_id" binding:"required"`
+	CharacterName *string `json:"character_name"`
+	BillingOrder  *int    `json:"billing_order"`
+}
+
+type UpdateCastRequest struct {
+	Cast []CastEntryRequest `json:"cast" binding:"dive"`
+}
+
+type CastMember struct {
+	PersonID      int     `json:"person_id"`
+	Name          string  `json:"name"`
+	CharacterName *string `json:"character_name"`
+	BillingOrder  *int    `json:"billing_order"`
+}
@@ -0,0 +1,72 @@
+package dto
+
+import "time"
+
+type SalesReportFilter struct {
+	GroupBy  string
+	From     time.Time
+	To       time.Time
+	MovieID  int
+	CinemaID int
+	// CinemaIDs restricts the report to a cinema manager's cinemas, nil means
+	// every cinema.
+	CinemaIDs []int
+}
+
+type SalesReportRow struct {
+	Key                   string   `json:"key"`
+	Label                 string   `json:"label"`
+	Revenue               float64  `json:"revenue"`
+	TicketsSold           int      `json:"tickets_sold"`
+	Capacity              *int     `json:"capacity"`
+	OccupancyRate         *float64 `json:"occupancy_rate"`
+	PaidTransactions      int      `json:"paid_transactions"`
+	CancelledTransactions int      `json:"cancelled_transactions"`
+	CancellationRate      float64  `json:"cancellation_rate"`
+}
+
+type SalesReport struct {
+	GroupBy string           `json:"group_by"`
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Totals  SalesReportRow   `json:"totals"`
+	Rows    []SalesReportRow `json:"rows"`
+}
+
+type DashboardSummary struct {
+	GeneratedAt          time.Time              `json:"generated_at"`
+	Today                DashboardSales         `json:"today"`
+	NewRegistrations     DashboardRegistrations `json:"new_registrations"`
+	LowestFill           []ShowtimeFill         `json:"lowest_fill"`
+	HighestFill          []ShowtimeFill         `json:"highest_fill"`
+	PendingTransactions  int                    `json:"pending_transactions"`
+	ExpiringTransactions []ExpiringTransaction  `json:"expiring_transactions"`
+}
+
+type DashboardSales struct {
+	Revenue      float64 `json:"revenue"`
+	TicketsSold  int     `json:"tickets_sold"`
+	Transactions int     `json:"transactions"`
+}
+
+type DashboardRegistrations struct {
+	Today     int `json:"today"`
+	Last7Days int `json:"last_7_days"`
+}
+
+type ShowtimeFill struct {
+	ShowtimeID   int       `json:"showtime_id"`
+	MovieTitle   string    `json:"movie_title"`
+	CinemaName   string    `json:"cinema_name"`
+	ShowDatetime time.Time `json:"show_datetime"`
+	TotalSeats   int       `json:"total_seats"`
+	SeatsBooked  int       `json:"seats_booked"`
+	FillRate     float64   `json:"fill_rate"`
+}
+
+type ExpiringTransaction struct {
+	TransactionCode string    `json:"transaction_code"`
+	RecipientEmail  string    `json:"recipient_email"`
+	TotalAmount     float64   `json:"total_amount"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+type ReviewRequest struct {
+	Rating int     `json:"rating" binding:"required,min=1,max=5"`
+	Body   *string `json:"body" binding:"omitempty,max=5000"`
+}
+
+type ModerateReviewRequest struct {
+	Status string  `json:"status" binding:"required,oneof=published hidden"`
+	Reason *string `json:"reason" binding:"omitempty,max=255"`
+}
+
+type ReviewFilter struct {
+	MovieID      *int
+	Status       string
+	VerifiedOnly bool
+	Sort         string
+}
+
+type ReviewResponse struct {
+	ID               int        `json:"id"`
+	MovieID          int        `json:"movie_id"`
+	UserID           int        `json:"user_id"`
+	ReviewerName     string     `json:"reviewer_name"`
+	Rating           int        `json:"rating"`
+	Body             *string    `json:"body"`
+	IsVerified       bool       `json:"is_verified"`
+	Status           string     `json:"status,omitempty"`
+	ModerationReason *string    `json:"moderation_reason,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+type PagedReviewsResponse struct {
+	PageInfo Pagination       `json:"page_info"`
+	Result   []ReviewResponse `json:"reviews"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type Suggestion struct {
+	Type       string  `json:"type"`
+	ID         int     `json:"id"`
+	Label      string  `json:"label"`
+	PosterPath *string `json:"poster_path,omitempty"`
+	Score      float64 `json:"score"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// ShowtimeTemplateRequest describes a weekly programme for one movie in one
+// cinema. Times maps a weekday (monday..sunday) to its show times as HH:MM.
+type ShowtimeTemplateRequest struct {
+	MovieID   int                 `json:"movie_id" binding:"required"`
+	CinemaID  int                 `json:"cinema_id" binding:"required"`
+	StartDate string              `json:"start_date" binding:"required" example:"2025-08-01"`
+	EndDate   string              `json:"end_date" binding:"required" example:"2025-08-31"`
+	Price     float64             `json:"price" binding:"required,gt=0"`
+	Times     map[string][]string `json:"times" binding:"required"`
+	DryRun    bool                `json:"dry_run"`
+}
+
+type GeneratedShowtime struct {
+	ShowtimeID   *int      `json:"showtime_id,omitempty"`
+	ShowDatetime time.Time `json:"show_datetime"`
+}
+
+type SkippedShowtime struct {
+	ShowDatetime       time.Time `json:"show_datetime"`
+	Reason             string    `json:"reason"`
+	ConflictShowtimeID *int      `json:"conflict_showtime_id,omitempty"`
+}
+
+type ShowtimeGenerationReport struct {
+	DryRun  bool                `json:"dry_run"`
+	Total   int                 `json:"total"`
+	Created []GeneratedShowtime `json:"created"`
+	Skipped []SkippedShowtime   `json:"skipped"`
+}
@@ -9,6 +9,9 @@ type CreateTransactionRequest struct {
 	RecipientFullName string   `json:"recipient_full_name" binding:"required"`
 	RecipientPhone    string   `json:"recipient_phone_number" binding:"required"`
 	PaymentMethodID   int      `json:"payment_method_id" binding:"required"`
+	// AgeAcknowledged confirms the buyer meets the movie's age rating. It is
+	// only consulted when the profile has no birth date.
+	AgeAcknowledged bool `json:"age_acknowledged"`
 }
 
 type ProcessPaymentRequest struct {
@@ -71,3 +74,115 @@ type TransactionListResponse struct {
 	Cinema          CinemaResponse   `json:"cinema"`
 	Seats           []string         `json:"seats"`
 }
+
+type TransactionFilter struct {
+	Email      string
+	Phone      string
+	CodePrefix string
+	Status     string
+	ShowtimeID int
+	From       *time.Time
+	To         *time.Time
+	// CinemaIDs restricts the search to a cinema manager's cinemas, nil means
+	// every cinema.
+	CinemaIDs []int
+}
+
+type AdminTransactionListItem struct {
+	TransactionID     int        `json:"transaction_id"`
+	TransactionCode   string     `json:"transaction_code"`
+	RecipientEmail    string     `json:"recipient_email"`
+	RecipientFullName string     `json:"recipient_full_name"`
+	RecipientPhone    string     `json:"recipient_phone_number"`
+	Status            string     `json:"status"`
+	TotalSeats        int        `json:"total_seats"`
+	TotalAmount       float64    `json:"total_amount"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	PaidAt            *time.Time `json:"paid_at"`
+	ShowtimeID        *int       `json:"showtime_id"`
+	ShowDatetime      *time.Time `json:"show_datetime"`
+	MovieTitle        *string    `json:"movie_title"`
+	CinemaName        *string    `json:"cinema_name"`
+	PaymentMethod     *string    `json:"payment_method"`
+}
+
+type PagedTransactionsResponse struct {
+	PageInfo Pagination                 `json:"page_info"`
+	Result   []AdminTransactionListItem `json:"transactions"`
+}
+
+type PaymentEventResponse struct {
+	ID         int       `json:"id"`
+	Event      string    `json:"event"`
+	ActorID    *int      `json:"actor_id"`
+	ActorEmail *string   `json:"actor_email"`
+	Note       *string   `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type PaymentMethodResponse struct {
+	PaymentMethodID int    `json:"payment_method_id"`
+	Name            string `json:"name"`
+	Code            string `json:"code"`
+}
+
+type TransactionDetailResponse struct {
+	Transaction   TransactionResponse    `json:"transaction"`
+	BuyerEmail    *string                `json:"buyer_email"`
+	Movie         MovieResponse          `json:"movie"`
+	Showtime      ShowtimeResponse       `json:"showtime"`
+	Cinema        CinemaResponse         `json:"cinema"`
+	PaymentMethod *PaymentMethodResponse `json:"payment_method"`
+	Tickets       []TicketResponse       `json:"tickets"`
+	Events        []PaymentEventResponse `json:"events"`
+}
+
+type TransactionActionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type CreateGroupBookingRequest struct {
+	CreateTransactionRequest
+	// Shares lists the invitees and how many seats each pays for. Seats not
+	// assigned to an invitee make up the organiser's own share.
+	Shares []GroupShareRequest `json:"shares" binding:"required,min=1,dive"`
+}
+
+type GroupShareRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Seats int    `json:"seats" binding:"required,min=1"`
+}
+
+type PayGroupShareRequest struct {
+	PaymentMethodID int    `json:"payment_method_id" binding:"required"`
+	PaymentProof    string `json:"payment_proof,omitempty"`
+}
+
+type GroupShareResponse struct {
+	ShareCode string     `json:"share_code"`
+	Email     string     `json:"email"`
+	Seats     int        `json:"seats"`
+	Amount    float64    `json:"amount"`
+	Status    string     `json:"status"`
+	PaidAt    *time.Time `json:"paid_at"`
+}
+
+type GroupBookingResponse struct {
+	Transaction       TransactionResponse  `json:"transaction"`
+	Tickets           []TicketResponse     `json:"tickets"`
+	Shares            []GroupShareResponse `json:"shares"`
+	PaidAmount        float64              `json:"paid_amount"`
+	OutstandingAmount float64              `json:"outstanding_amount"`
+}
+
+type GroupShareDetailResponse struct {
+	Share           GroupShareResponse `json:"share"`
+	TransactionCode string             `json:"transaction_code"`
+	OrganiserName   string             `json:"organiser_name"`
+	GroupStatus     string             `json:"group_status"`
+	ExpiresAt       time.Time          `json:"expires_at"`
+	Movie           MovieResponse      `json:"movie"`
+	Showtime        ShowtimeResponse   `json:"showtime"`
+	Cinema          CinemaResponse     `json:"cinema"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "time"
+
+type UserFilter struct {
+	Search string
+	Role   string
+	Status string
+}
+
+type UpdateRoleRequest struct {
+	Role      string `json:"role" binding:"required"`
+	CinemaIDs []int  `json:"cinema_ids"`
+}
+
+type UpdateUserStatusRequest struct {
+	IsActive *bool `json:"is_active" binding:"required"`
+}
+
+type UserListItem struct {
+	UserID      int        `json:"user_id"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	IsActive    bool       `json:"is_active"`
+	FirstName   *string    `json:"first_name"`
+	LastName    *string    `json:"last_name"`
+	PhoneNumber *string    `json:"phone_number"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLogin   *time.Time `json:"last_login"`
+}
+
+type UserDetailResponse struct {
+	User           UserListItem              `json:"user"`
+	CinemaIDs      []int                     `json:"cinema_ids"`
+	BookingHistory []TransactionListResponse `json:"booking_history"`
+}
+
+type PagedUsersResponse struct {
+	PageInfo Pagination     `json:"page_info"`
+	Result   []UserListItem `json:"users"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type WatchlistRequest struct {
+	MovieID int `json:"movie_id" binding:"required"`
+}
+
+type WatchlistItem struct {
+	MovieID       int        `json:"movie_id"`
+	Title         string     `json:"title"`
+	PosterPath    *string    `json:"poster_path"`
+	ReleaseDate   time.Time  `json:"release_date"`
+	FirstShowtime *time.Time `json:"first_showtime"`
+	AddedAt       time.Time  `json:"added_at"`
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"noir-backend/container"
+	"noir-backend/migrations"
 	"noir-backend/router"
 	"noir-backend/utils"
+	"os"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 //@title NOIR RESTful API
@@ -19,27 +24,67 @@ import (
 //@name	Authorization
 
 func main() {
+	if err := runCommand(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func serve() error {
+	if _, err := utils.LoadKeyring(); err != nil {
+		return fmt.Errorf("jwt signing key is not configured: %w", err)
+	}
+
 	dbpool, err := utils.ConnectDB()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	defer dbpool.Close()
 
-	// ctx, cancel := context.WithCancel(context.Background())
-	// defer cancel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// utils.StartTransactionExpiryJob(ctx, dbpool)
-	// seeder.SeedTMDBMovies(dbpool)
-	// seeder.SeedAdminUser(dbpool)
+	if err := prepareSchema(ctx, dbpool); err != nil {
+		return err
+	}
 
 	redis := utils.InitRedis()
 
 	c := container.NewContainer(dbpool, redis)
 
+	c.WatchlistService.StartReminderJob(ctx, utils.Load().Watchlist.ReminderInterval)
+	c.TransactionService.StartExpiryJob(ctx, utils.Load().Transaction.ExpiryInterval)
+
 	r := gin.Default()
+	if err := r.SetTrustedProxies(utils.Load().TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
 
 	router.CombineRouter(r, c)
 
-	r.Run(":9503")
 	log.Println("server runnng on port 9503")
+	return r.Run(":9503")
+}
+
+// prepareSchema applies pending migrations when AUTO_MIGRATE is on and
+// refuses to serve against a schema this build was not written for.
+func prepareSchema(ctx context.Context, db *pgxpool.Pool) error {
+	all, err := utils.LoadMigrations(migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	if utils.Load().AutoMigrate {
+		applied, err := utils.MigrateUp(ctx, db, all)
+		if err != nil {
+			return err
+		}
+		if applied > 0 {
+			log.Printf("applied %d migration(s)", applied)
+		}
+	}
+
+	if err := utils.CheckSchemaVersion(ctx, db, all); err != nil {
+		return fmt.Errorf("%w, run `noir migrate up`", err)
+	}
+	return nil
 }
@@ -2,16 +2,22 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"noir-backend/utils"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
 )
 
 func AuthMiddleware() gin.HandlerFunc {
+	rdb := utils.InitRedis()
+
 	return func(c *gin.Context) {
 		defer func() {
 			if r := recover(); r != nil {
@@ -35,22 +41,80 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		expCmd := utils.InitRedis().Exists(context.Background(), fmt.Sprintf("blacklist-token:%s", tokenString))
-		if expCmd.Val() != 0 {
-			utils.SendError(c, http.StatusUnauthorized, "Expired token")
-			c.Abort()
-			return
-		}
-
-		claims, err := utils.ValidateToken(tokenString)
+		claims, status, err := authenticate(rdb, tokenString)
 		if err != nil {
-			utils.SendError(c, http.StatusUnauthorized, err.Error())
+			utils.SendError(c, status, err.Error())
 			c.Abort()
 			return
 		}
 
-		c.Set("user_id", int(claims["user_id"].(float64)))
-		c.Set("role", claims["role"])
+		setIdentity(c, claims)
 		c.Next()
 	}
 }
+
+// OptionalAuth identifies the caller when a valid bearer token is sent but lets
+// anonymous requests and requests with unusable tokens through unidentified.
+func OptionalAuth() gin.HandlerFunc {
+	rdb := utils.InitRedis()
+
+	return func(c *gin.Context) {
+		tokenString, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if ok && tokenString != "" {
+			if claims, _, err := authenticate(rdb, tokenString); err == nil {
+				setIdentity(c, claims)
+			}
+		}
+		c.Next()
+	}
+}
+
+// authenticate validates tokenString against its signature and the session
+// state kept in Redis. It fails closed when Redis cannot be reached, since a
+// revoked session or disabled account would otherwise get through.
+func authenticate(rdb *redis.Client, tokenString string) (jwt.MapClaims, int, error) {
+	blacklisted, err := rdb.Exists(context.Background(), fmt.Sprintf("blacklist-token:%s", tokenString)).Result()
+	if err != nil {
+		log.Printf("session store unavailable: %v", err)
+		return nil, http.StatusServiceUnavailable, errors.New("Session store unavailable")
+	}
+	if blacklisted != 0 {
+		return nil, http.StatusUnauthorized, errors.New("Expired token")
+	}
+
+	claims, err := utils.ValidateToken(tokenString)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	userID := int(claims["user_id"].(float64))
+
+	disabled, err := rdb.Exists(context.Background(), fmt.Sprintf("disabled-user:%d", userID)).Result()
+	if err != nil {
+		log.Printf("session store unavailable: %v", err)
+		return nil, http.StatusServiceUnavailable, errors.New("Session store unavailable")
+	}
+	if disabled != 0 {
+		return nil, http.StatusForbidden, errors.New("Account disabled")
+	}
+
+	revokedBefore, err := rdb.Get(context.Background(), fmt.Sprintf("revoked-before:%d", userID)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("session store unavailable: %v", err)
+		return nil, http.StatusServiceUnavailable, errors.New("Session store unavailable")
+	}
+	if err == nil {
+		issuedAt, _ := claims["iat"].(float64)
+		if int64(math.Round(issuedAt*1000)) <= revokedBefore {
+			return nil, http.StatusUnauthorized, errors.New("Expired token")
+		}
+	}
+
+	return claims, http.StatusOK, nil
+}
+
+func setIdentity(c *gin.Context, claims jwt.MapClaims) {
+	c.Set("user_id", int(claims["user_id"].(float64)))
+	c.Set("role", claims["role"])
+	c.Set("mfa", claims["mfa"] == true)
+}
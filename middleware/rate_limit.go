@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"noir-backend/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit allows at most limit requests per client IP in each fixed window.
+// Counters are kept in Redis so the limit holds across instances. Requests are
+// let through when Redis is unavailable.
+func RateLimit(scope string, limit int, window time.Duration) gin.HandlerFunc {
+	rdb := utils.InitRedis()
+
+	return func(c *gin.Context) {
+		if limit <= 0 || window < time.Second {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		bucket := now.Unix() / int64(window.Seconds())
+		key := fmt.Sprintf("rate-limit:%s:%s:%d", scope, c.ClientIP(), bucket)
+
+		count, err := rdb.Incr(context.Background(), key).Result()
+		if err != nil {
+			log.Printf("rate limit unavailable: %v", err)
+			c.Next()
+			return
+		}
+		if count == 1 {
+			rdb.Expire(context.Background(), key, window)
+		}
+
+		remaining := max(int64(limit)-count, 0)
+		resetAt := time.Unix((bucket+1)*int64(window.Seconds()), 0)
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		if count > int64(limit) {
+			c.Header("Retry-After", strconv.Itoa(int(resetAt.Sub(now).Seconds())+1))
+			utils.SendError(c, http.StatusTooManyRequests, "Too many requests")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"noir-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// RequestID tags every request with an ID, reusing the one set by a proxy in
+// X-Request-ID, and passes it with the client IP to services through the
+// request context. The IP only honours X-Forwarded-For from TRUSTED_PROXIES.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > 100 {
+			id = uuid.New().String()
+		}
+
+		c.Set("request_id", id)
+		c.Header(requestIDHeader, id)
+		c.Request = c.Request.WithContext(utils.WithRequestInfo(c.Request.Context(), utils.RequestInfo{
+			ID: id,
+			IP: c.ClientIP(),
+		}))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"net/http"
+	"noir-backend/services"
+	"noir-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == "" {
+			utils.SendError(c, http.StatusUnauthorized, "Status Unauthorized")
+			c.Abort()
+			return
+		}
+
+		for _, r := range roles {
+			if r == role {
+				c.Next()
+				return
+			}
+		}
+
+		utils.SendError(c, http.StatusForbidden, "you don't have access to this resource")
+		c.Abort()
+	}
+}
+
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		if role == "" {
+			utils.SendError(c, http.StatusUnauthorized, "Status Unauthorized")
+			c.Abort()
+			return
+		}
+
+		for _, p := range permissions {
+			if !utils.HasPermission(role, p) {
+				utils.SendError(c, http.StatusForbidden, "you don't have permission to access this resource")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// RequireTwoFactor rejects admin sessions that were not confirmed with a
+// second factor when REQUIRE_ADMIN_2FA is enabled.
+func RequireTwoFactor() gin.HandlerFunc {
+	required := utils.Load().TwoFactor.RequiredForAdmin
+
+	return func(c *gin.Context) {
+		if required && c.GetString("role") == utils.RoleAdmin && !c.GetBool("mfa") {
+			utils.SendError(c, http.StatusForbidden, "two-factor authentication required")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// CinemaScope loads the cinemas assigned to a cinema manager so handlers can
+// restrict them to their own cinemas with CanAccessCinema.
+func CinemaScope(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != utils.RoleCinemaManager {
+			c.Next()
+			return
+		}
+
+		cinemaIDs, err := authService.GetManagedCinemaIDs(c.Request.Context(), c.GetInt("user_id"))
+		if err != nil {
+			utils.SendError(c, http.StatusInternalServerError, "failed to load managed cinemas")
+			c.Abort()
+			return
+		}
+
+		c.Set("cinema_ids", cinemaIDs)
+		c.Next()
+	}
+}
+
+// CanAccessCinema reports whether the current user may manage the cinema.
+// Only cinema managers are scoped, other staff roles see every cinema.
+func CanAccessCinema(c *gin.Context, cinemaID int) bool {
+	if c.GetString("role") != utils.RoleCinemaManager {
+		return true
+	}
+
+	cinemaIDs, ok := c.Get("cinema_ids")
+	if !ok {
+		return false
+	}
+
+	for _, id := range cinemaIDs.([]int) {
+		if id == cinemaID {
+			return true
+		}
+	}
+	return false
+}
+
+// ManagedCinemaIDs returns the cinema scope of the current user, or nil when
+// the user is not restricted to specific cinemas.
+func ManagedCinemaIDs(c *gin.Context) []int {
+	if c.GetString("role") != utils.RoleCinemaManager {
+		return nil
+	}
+
+	cinemaIDs, ok := c.Get("cinema_ids")
+	if !ok {
+		return []int{}
+	}
+	return cinemaIDs.([]int)
+}
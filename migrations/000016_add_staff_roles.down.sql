@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS cinema_managers;
+
+UPDATE users SET role = 'user' WHERE role NOT IN ('user', 'admin');
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users
+ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));
\ No newline at end of file
@@ -0,0 +1,20 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+
+ALTER TABLE users
+ADD CONSTRAINT users_role_check CHECK (
+    role IN (
+        'user',
+        'admin',
+        'cinema_manager',
+        'usher',
+        'support'
+    )
+);
+
+CREATE TABLE cinema_managers (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    cinema_id INTEGER NOT NULL REFERENCES cinemas (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, cinema_id)
+);
\ No newline at end of file
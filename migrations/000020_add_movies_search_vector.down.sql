@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS movies_cast_search_vector_trigger ON movies_cast;
+
+DROP FUNCTION IF EXISTS movies_cast_search_vector_refresh;
+
+DROP TRIGGER IF EXISTS movies_search_vector_trigger ON movies;
+
+DROP FUNCTION IF EXISTS movies_search_vector_update;
+
+DROP INDEX IF EXISTS idx_movies_search_vector;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
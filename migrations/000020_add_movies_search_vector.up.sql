@@ -0,0 +1,39 @@
+ALTER TABLE movies ADD COLUMN search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION movies_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce((
+            SELECT string_agg(a.first_name || ' ' || a.last_name, ' ')
+            FROM movies_cast mc
+            JOIN actors a ON a.id = mc.actor_id
+            WHERE mc.movie_id = NEW.id
+        ), '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(NEW.overview, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movies_search_vector_trigger
+BEFORE INSERT OR UPDATE OF title, overview ON movies
+FOR EACH ROW EXECUTE FUNCTION movies_search_vector_update();
+
+CREATE OR REPLACE FUNCTION movies_cast_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE movies SET title = title WHERE id = OLD.movie_id;
+    ELSE
+        UPDATE movies SET title = title WHERE id = NEW.movie_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER movies_cast_search_vector_trigger
+AFTER INSERT OR UPDATE OR DELETE ON movies_cast
+FOR EACH ROW EXECUTE FUNCTION movies_cast_search_vector_refresh();
+
+UPDATE movies SET title = title;
+
+CREATE INDEX idx_movies_search_vector ON movies USING GIN (search_vector);
\ No newline at end of file
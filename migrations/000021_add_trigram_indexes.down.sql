@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_directors_name_trgm;
+
+DROP INDEX IF EXISTS idx_actors_name_trgm;
+
+DROP INDEX IF EXISTS idx_movies_title_trgm;
\ No newline at end of file
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_movies_title_trgm ON movies USING GIN (title gin_trgm_ops);
+
+CREATE INDEX idx_actors_name_trgm ON actors USING GIN ((first_name || ' ' || last_name) gin_trgm_ops);
+
+CREATE INDEX idx_directors_name_trgm ON directors USING GIN ((first_name || ' ' || last_name) gin_trgm_ops);
\ No newline at end of file
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_movies_cast_billing;
+
+ALTER TABLE movies_cast DROP COLUMN IF EXISTS billing_order;
+
+ALTER TABLE movies_cast RENAME COLUMN character_name TO role;
+
+ALTER TABLE directors DROP COLUMN IF EXISTS person_id;
+
+ALTER TABLE actors DROP COLUMN IF EXISTS person_id;
+
+DROP TABLE IF EXISTS people;
\ No newline at end of file
@@ -0,0 +1,64 @@
+CREATE TABLE people (
+    id SERIAL PRIMARY KEY,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    bio TEXT,
+    photo_path VARCHAR(500),
+    birth_date DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_people_name_trgm ON people USING GIN ((first_name || ' ' || last_name) gin_trgm_ops);
+
+-- Collapse actors and directors stored more than once under the same name.
+UPDATE movies_cast mc
+SET actor_id = keep.id
+FROM actors a
+JOIN (SELECT MIN(id) AS id, first_name, last_name FROM actors GROUP BY first_name, last_name) keep
+    ON keep.first_name = a.first_name AND keep.last_name = a.last_name
+WHERE mc.actor_id = a.id AND a.id <> keep.id;
+
+DELETE FROM actors a USING actors b
+WHERE a.first_name = b.first_name AND a.last_name = b.last_name AND a.id > b.id;
+
+UPDATE movies m
+SET director_id = keep.id
+FROM directors d
+JOIN (SELECT MIN(id) AS id, first_name, last_name FROM directors GROUP BY first_name, last_name) keep
+    ON keep.first_name = d.first_name AND keep.last_name = d.last_name
+WHERE m.director_id = d.id AND d.id <> keep.id;
+
+DELETE FROM directors a USING directors b
+WHERE a.first_name = b.first_name AND a.last_name = b.last_name AND a.id > b.id;
+
+INSERT INTO people (first_name, last_name)
+SELECT first_name, last_name FROM actors
+UNION
+SELECT first_name, last_name FROM directors;
+
+ALTER TABLE actors ADD COLUMN person_id INTEGER REFERENCES people (id) ON DELETE CASCADE;
+
+ALTER TABLE directors ADD COLUMN person_id INTEGER REFERENCES people (id) ON DELETE CASCADE;
+
+UPDATE actors a SET person_id = p.id
+FROM people p
+WHERE p.first_name = a.first_name AND p.last_name = a.last_name;
+
+UPDATE directors d SET person_id = p.id
+FROM people p
+WHERE p.first_name = d.first_name AND p.last_name = d.last_name;
+
+ALTER TABLE actors
+ALTER COLUMN person_id SET NOT NULL,
+ADD CONSTRAINT actors_person_id_key UNIQUE (person_id);
+
+ALTER TABLE directors
+ALTER COLUMN person_id SET NOT NULL,
+ADD CONSTRAINT directors_person_id_key UNIQUE (person_id);
+
+ALTER TABLE movies_cast RENAME COLUMN role TO character_name;
+
+ALTER TABLE movies_cast ADD COLUMN billing_order INTEGER;
+
+CREATE INDEX idx_movies_cast_billing ON movies_cast (movie_id, billing_order);
\ No newline at end of file
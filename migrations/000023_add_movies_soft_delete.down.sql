@@ -0,0 +1,8 @@
+ALTER TABLE showtimes DROP CONSTRAINT showtimes_movie_id_fkey;
+
+ALTER TABLE showtimes
+ADD CONSTRAINT showtimes_movie_id_fkey FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_movies_not_deleted;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS deleted_at;
\ No newline at end of file
@@ -0,0 +1,10 @@
+ALTER TABLE movies ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_movies_not_deleted ON movies (release_date) WHERE deleted_at IS NULL;
+
+-- Movies are soft deleted now, so a hard delete must not wipe showtimes and
+-- the tickets sold for them.
+ALTER TABLE showtimes DROP CONSTRAINT showtimes_movie_id_fkey;
+
+ALTER TABLE showtimes
+ADD CONSTRAINT showtimes_movie_id_fkey FOREIGN KEY (movie_id) REFERENCES movies (id) ON DELETE RESTRICT;
\ No newline at end of file
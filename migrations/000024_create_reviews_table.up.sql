@@ -0,0 +1,19 @@
+CREATE TABLE reviews (
+    id SERIAL PRIMARY KEY,
+    movie_id INTEGER NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body TEXT,
+    is_verified BOOLEAN NOT NULL DEFAULT false,
+    status VARCHAR(20) NOT NULL DEFAULT 'published' CHECK (
+        status IN ('published', 'hidden')
+    ),
+    moderated_by INTEGER REFERENCES users (id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP,
+    moderation_reason VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (movie_id, user_id)
+);
+
+CREATE INDEX idx_reviews_movie_status ON reviews (movie_id, status, created_at DESC);
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE watchlist (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    movie_id INTEGER NOT NULL REFERENCES movies (id) ON DELETE CASCADE,
+    on_sale_notified_at TIMESTAMP,
+    release_notified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, movie_id)
+);
+
+CREATE INDEX idx_watchlist_movie ON watchlist (movie_id);
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE profile DROP COLUMN IF EXISTS birth_date;
+
+DROP INDEX IF EXISTS idx_movies_content_advisories;
+
+ALTER TABLE movies
+DROP COLUMN IF EXISTS content_advisories,
+DROP COLUMN IF EXISTS age_rating;
\ No newline at end of file
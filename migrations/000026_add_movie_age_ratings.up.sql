@@ -0,0 +1,9 @@
+ALTER TABLE movies
+ADD COLUMN age_rating VARCHAR(5) NOT NULL DEFAULT 'SU' CHECK (
+    age_rating IN ('SU', '13+', '17+', '21+')
+),
+ADD COLUMN content_advisories TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX idx_movies_content_advisories ON movies USING GIN (content_advisories);
+
+ALTER TABLE profile ADD COLUMN birth_date DATE;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE genres DROP COLUMN IF EXISTS tmdb_id;
+
+ALTER TABLE people DROP COLUMN IF EXISTS tmdb_id;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS tmdb_id;
@@ -0,0 +1,11 @@
+ALTER TABLE movies ADD COLUMN tmdb_id INTEGER;
+
+ALTER TABLE movies ADD CONSTRAINT movies_tmdb_id_key UNIQUE (tmdb_id);
+
+ALTER TABLE people ADD COLUMN tmdb_id INTEGER;
+
+ALTER TABLE people ADD CONSTRAINT people_tmdb_id_key UNIQUE (tmdb_id);
+
+ALTER TABLE genres ADD COLUMN tmdb_id INTEGER;
+
+ALTER TABLE genres ADD CONSTRAINT genres_tmdb_id_key UNIQUE (tmdb_id);
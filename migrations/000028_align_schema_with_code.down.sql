@@ -0,0 +1,75 @@
+CREATE OR REPLACE FUNCTION movies_cast_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE movies SET title = title WHERE id = OLD.movie_id;
+    ELSE
+        UPDATE movies SET title = title WHERE id = NEW.movie_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION movies_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce((
+            SELECT string_agg(a.first_name || ' ' || a.last_name, ' ')
+            FROM movies_cast mc
+            JOIN actors a ON a.id = mc.actor_id
+            WHERE mc.movie_id = NEW.id
+        ), '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(NEW.overview, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE transactions SET status = 'cancelled' WHERE status = 'expired';
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+
+ALTER TABLE transactions
+ADD CONSTRAINT transactions_status_check CHECK (
+    status IN (
+        'pending',
+        'paid',
+        'cancelled'
+    )
+);
+
+ALTER TABLE users
+ADD COLUMN profile_id INTEGER UNIQUE REFERENCES profile (profile_id) ON DELETE SET NULL;
+
+UPDATE users u SET profile_id = p.profile_id
+FROM profile p
+WHERE p.user_id = u.user_id;
+
+ALTER TABLE profile DROP COLUMN user_id;
+
+UPDATE profile SET first_name = '' WHERE first_name IS NULL;
+
+UPDATE profile SET last_name = '' WHERE last_name IS NULL;
+
+ALTER TABLE profile
+ALTER COLUMN first_name SET NOT NULL,
+ALTER COLUMN last_name SET NOT NULL;
+
+ALTER TABLE profile RENAME COLUMN avatar_path TO avatar;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS updated_at;
+
+ALTER TABLE users RENAME COLUMN password_hash TO password;
+
+ALTER TABLE tickets RENAME COLUMN ticket_id TO id;
+
+ALTER TABLE transactions RENAME COLUMN transaction_id TO id;
+
+ALTER TABLE users RENAME COLUMN user_id TO id;
+
+ALTER TABLE profile RENAME COLUMN profile_id TO id;
+
+ALTER TABLE payment_method RENAME COLUMN payment_method_id TO id;
+
+ALTER TABLE showtimes RENAME COLUMN showtime_id TO id;
+
+ALTER TABLE movies RENAME COLUMN movie_id TO id;
@@ -0,0 +1,86 @@
+-- Primary keys of these tables are queried as <table>_id by the services.
+ALTER TABLE movies RENAME COLUMN id TO movie_id;
+
+ALTER TABLE showtimes RENAME COLUMN id TO showtime_id;
+
+ALTER TABLE payment_method RENAME COLUMN id TO payment_method_id;
+
+ALTER TABLE profile RENAME COLUMN id TO profile_id;
+
+ALTER TABLE users RENAME COLUMN id TO user_id;
+
+ALTER TABLE transactions RENAME COLUMN id TO transaction_id;
+
+ALTER TABLE tickets RENAME COLUMN id TO ticket_id;
+
+ALTER TABLE users RENAME COLUMN password TO password_hash;
+
+ALTER TABLE movies ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
+
+UPDATE movies SET updated_at = created_at;
+
+-- A profile now points to its user instead of users.profile_id, and is
+-- created empty on registration.
+ALTER TABLE profile RENAME COLUMN avatar TO avatar_path;
+
+ALTER TABLE profile
+ALTER COLUMN first_name DROP NOT NULL,
+ALTER COLUMN last_name DROP NOT NULL;
+
+ALTER TABLE profile ADD COLUMN user_id INTEGER REFERENCES users (user_id) ON DELETE CASCADE;
+
+UPDATE profile p SET user_id = u.user_id
+FROM users u
+WHERE u.profile_id = p.profile_id;
+
+DELETE FROM profile WHERE user_id IS NULL;
+
+INSERT INTO profile (user_id)
+SELECT u.user_id FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM profile p WHERE p.user_id = u.user_id);
+
+ALTER TABLE profile
+ALTER COLUMN user_id SET NOT NULL,
+ADD CONSTRAINT profile_user_id_key UNIQUE (user_id);
+
+ALTER TABLE users DROP COLUMN profile_id;
+
+-- The expiry job marks unpaid transactions as expired.
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_status_check;
+
+ALTER TABLE transactions
+ADD CONSTRAINT transactions_status_check CHECK (
+    status IN (
+        'pending',
+        'paid',
+        'cancelled',
+        'expired'
+    )
+);
+
+-- The search vector triggers still refer to movies.id.
+CREATE OR REPLACE FUNCTION movies_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('english', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce((
+            SELECT string_agg(a.first_name || ' ' || a.last_name, ' ')
+            FROM movies_cast mc
+            JOIN actors a ON a.id = mc.actor_id
+            WHERE mc.movie_id = NEW.movie_id
+        ), '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(NEW.overview, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION movies_cast_search_vector_refresh() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE movies SET title = title WHERE movie_id = OLD.movie_id;
+    ELSE
+        UPDATE movies SET title = title WHERE movie_id = NEW.movie_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
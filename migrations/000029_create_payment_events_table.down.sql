@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_recipient_email;
+
+DROP INDEX IF EXISTS idx_transactions_created_at;
+
+DROP TABLE IF EXISTS payment_events;
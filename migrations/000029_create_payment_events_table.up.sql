@@ -0,0 +1,22 @@
+CREATE TABLE payment_events (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (transaction_id) ON DELETE CASCADE,
+    event VARCHAR(30) NOT NULL CHECK (
+        event IN (
+            'created',
+            'paid',
+            'cancelled',
+            'expired',
+            'confirmation_sent'
+        )
+    ),
+    actor_id INTEGER REFERENCES users (user_id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_events_transaction ON payment_events (transaction_id, created_at);
+
+CREATE INDEX idx_transactions_created_at ON transactions (created_at);
+
+CREATE INDEX idx_transactions_recipient_email ON transactions (LOWER(recipient_email));
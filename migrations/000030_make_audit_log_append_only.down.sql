@@ -0,0 +1,24 @@
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON audit_log;
+
+DROP TRIGGER IF EXISTS audit_log_no_update_delete ON audit_log;
+
+DROP FUNCTION IF EXISTS audit_log_append_only();
+
+DROP INDEX IF EXISTS idx_audit_log_request;
+
+DROP INDEX IF EXISTS idx_audit_log_action;
+
+DROP INDEX IF EXISTS idx_audit_log_actor;
+
+DROP INDEX IF EXISTS idx_audit_log_created_at;
+
+UPDATE audit_log SET actor_id = NULL
+WHERE actor_id IS NOT NULL AND actor_id NOT IN (SELECT user_id FROM users);
+
+ALTER TABLE audit_log
+ADD CONSTRAINT audit_log_actor_id_fkey FOREIGN KEY (actor_id) REFERENCES users (user_id) ON DELETE SET NULL;
+
+ALTER TABLE audit_log
+DROP COLUMN request_id,
+DROP COLUMN ip_address,
+DROP COLUMN changes;
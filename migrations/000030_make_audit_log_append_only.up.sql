@@ -0,0 +1,29 @@
+ALTER TABLE audit_log
+ADD COLUMN changes JSONB,
+ADD COLUMN ip_address VARCHAR(45),
+ADD COLUMN request_id VARCHAR(100);
+
+-- Entries must outlive the users they mention, and SET NULL would be an update.
+ALTER TABLE audit_log DROP CONSTRAINT IF EXISTS audit_log_actor_id_fkey;
+
+CREATE INDEX idx_audit_log_created_at ON audit_log (created_at);
+
+CREATE INDEX idx_audit_log_actor ON audit_log (actor_id, created_at);
+
+CREATE INDEX idx_audit_log_action ON audit_log (action);
+
+CREATE INDEX idx_audit_log_request ON audit_log (request_id);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update_delete
+BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+BEFORE TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,15 @@
+DELETE FROM payment_events WHERE event = 'share_paid';
+
+ALTER TABLE payment_events DROP CONSTRAINT payment_events_event_check;
+
+ALTER TABLE payment_events ADD CONSTRAINT payment_events_event_check CHECK (
+    event IN (
+        'created',
+        'paid',
+        'cancelled',
+        'expired',
+        'confirmation_sent'
+    )
+);
+
+DROP TABLE IF EXISTS group_booking_shares;
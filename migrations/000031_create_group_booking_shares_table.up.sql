@@ -0,0 +1,36 @@
+CREATE TABLE group_booking_shares (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (transaction_id) ON DELETE CASCADE,
+    share_code VARCHAR(50) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
+    seats INTEGER NOT NULL CHECK (seats > 0),
+    amount DECIMAL(10, 2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (
+        status IN (
+            'pending',
+            'paid',
+            'cancelled',
+            'refund_due'
+        )
+    ),
+    payment_method_id INTEGER REFERENCES payment_method (payment_method_id),
+    paid_by INTEGER REFERENCES users (user_id) ON DELETE SET NULL,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (transaction_id, email)
+);
+
+CREATE INDEX idx_group_booking_shares_email ON group_booking_shares (LOWER(email));
+
+ALTER TABLE payment_events DROP CONSTRAINT payment_events_event_check;
+
+ALTER TABLE payment_events ADD CONSTRAINT payment_events_event_check CHECK (
+    event IN (
+        'created',
+        'paid',
+        'cancelled',
+        'expired',
+        'confirmation_sent',
+        'share_paid'
+    )
+);
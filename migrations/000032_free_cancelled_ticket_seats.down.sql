@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_tickets_showtime_seat;
+
+-- Rebooked seats would violate the old constraint, keep only the latest
+-- ticket of each seat.
+DELETE FROM tickets t
+USING tickets newer
+WHERE newer.showtime_id = t.showtime_id
+  AND newer.seat_number = t.seat_number
+  AND newer.ticket_id > t.ticket_id;
+
+ALTER TABLE tickets ADD CONSTRAINT tickets_showtime_id_seat_number_key UNIQUE (showtime_id, seat_number);
@@ -0,0 +1,6 @@
+-- Cancelled tickets give their seat back, so only live tickets must be unique
+-- per seat.
+ALTER TABLE tickets DROP CONSTRAINT tickets_showtime_id_seat_number_key;
+
+CREATE UNIQUE INDEX idx_tickets_showtime_seat ON tickets (showtime_id, seat_number)
+WHERE status <> 'cancelled';
@@ -0,0 +1,3 @@
+-- Time step of the last accepted TOTP code, codes at or before it are
+-- rejected so they cannot be replayed.
+ALTER TABLE users ADD COLUMN totp_last_step BIGINT;
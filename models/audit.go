@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+type AuditLog struct {
+	ID         int                    `json:"id" db:"id"`
+	ActorID    *int                   `json:"actor_id" db:"actor_id"`
+	Action     string                 `json:"action" db:"action"`
+	EntityType string                 `json:"entity_type" db:"entity_type"`
+	EntityID   *string                `json:"entity_id" db:"entity_id"`
+	Metadata   map[string]any         `json:"metadata" db:"metadata"`
+	Changes    map[string]AuditChange `json:"changes" db:"changes"`
+	IPAddress  *string                `json:"ip_address" db:"ip_address"`
+	RequestID  *string                `json:"request_id" db:"request_id"`
+	CreatedAt  time.Time              `json:"created_at" db:"created_at"`
+}
+
+// AuditChange is the value of a field before and after an audited change.
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
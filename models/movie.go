@@ -5,33 +5,38 @@ import (
 )
 
 type Movie struct {
-	MovieID      int       `json:"movie_id" db:"movie_id"`
-	Title        string    `json:"title" db:"title"`
-	PosterPath   *string   `json:"poster_path" db:"poster_path"`
-	BackdropPath *string   `json:"backdrop_path" db:"backdrop_path"`
-	Overview     string    `json:"overview" db:"overview"`
-	Duration     int       `json:"duration" db:"duration"`
-	ReleaseDate  time.Time `json:"release_date" db:"release_date"`
-	DirectorID   *int      `json:"director_id" db:"director_id"`
-	CreatedAt    time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	MovieID           int       `json:"movie_id" db:"movie_id"`
+	Title             string    `json:"title" db:"title"`
+	PosterPath        *string   `json:"poster_path" db:"poster_path"`
+	BackdropPath      *string   `json:"backdrop_path" db:"backdrop_path"`
+	Overview          string    `json:"overview" db:"overview"`
+	Duration          int       `json:"duration" db:"duration"`
+	ReleaseDate       time.Time `json:"release_date" db:"release_date"`
+	DirectorID        *int      `json:"director_id" db:"director_id"`
+	AgeRating         string    `json:"age_rating" db:"age_rating"`
+	ContentAdvisories []string  `json:"content_advisories" db:"content_advisories"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
 }
 
 type MovieCast struct {
-	ID      int    `json:"id" db:"id"`
-	MovieID int    `json:"movie_id" db:"movie_id"`
-	ActorID int    `json:"actor_id" db:"actor_id"`
-	Role    string `json:"role" db:"role"`
+	ID            int     `json:"id" db:"id"`
+	MovieID       int     `json:"movie_id" db:"movie_id"`
+	ActorID       int     `json:"actor_id" db:"actor_id"`
+	CharacterName *string `json:"character_name" db:"character_name"`
+	BillingOrder  *int    `json:"billing_order" db:"billing_order"`
 }
 
 type Actor struct {
 	ID        int    `json:"id" db:"id"`
+	PersonID  int    `json:"person_id" db:"person_id"`
 	FirstName string `json:"first_name" db:"first_name"`
 	LastName  string `json:"last_name" db:"last_name"`
 }
 
 type Director struct {
 	DirectorID int    `json:"director_id" db:"id"`
+	PersonID   int    `json:"person_id" db:"person_id"`
 	FirstName  string `json:"first_name" db:"first_name"`
 	LastName   string `json:"last_name" db:"last_name"`
 }
@@ -48,16 +53,20 @@ type Genre struct {
 }
 
 type MovieJoinRow struct {
-	MovieID      int       `db:"movie_id"`
-	Title        string    `db:"title"`
-	PosterPath   *string   `db:"poster_path"`
-	BackdropPath *string   `db:"backdrop_path"`
-	Overview     string    `db:"overview"`
-	Duration     int       `db:"duration"`
-	ReleaseDate  time.Time `db:"release_date"`
-	CreatedAt    time.Time `db:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at"`
-	Director     *string   `db:"director"`
-	Genres       *[]string `db:"genres"`
-	Cast         *[]string `db:"cast"`
+	MovieID           int       `db:"movie_id"`
+	Title             string    `db:"title"`
+	PosterPath        *string   `db:"poster_path"`
+	BackdropPath      *string   `db:"backdrop_path"`
+	Overview          string    `db:"overview"`
+	Duration          int       `db:"duration"`
+	ReleaseDate       time.Time `db:"release_date"`
+	AgeRating         string    `db:"age_rating"`
+	ContentAdvisories []string  `db:"content_advisories"`
+	CreatedAt         time.Time `db:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at"`
+	Director          *string   `db:"director"`
+	Genres            *[]string `db:"genres"`
+	Cast              *[]string `db:"cast"`
+	Rating            *float64  `db:"rating"`
+	RatingCount       int       `db:"rating_count"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+type Person struct {
+	ID        int        `json:"id" db:"id"`
+	FirstName string     `json:"first_name" db:"first_name"`
+	LastName  string     `json:"last_name" db:"last_name"`
+	Bio       *string    `json:"bio" db:"bio"`
+	PhotoPath *string    `json:"photo_path" db:"photo_path"`
+	BirthDate *time.Time `json:"birth_date" db:"birth_date"`
+	TMDBID    *int       `json:"tmdb_id" db:"tmdb_id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+type Review struct {
+	ID               int        `json:"id" db:"id"`
+	MovieID          int        `json:"movie_id" db:"movie_id"`
+	UserID           int        `json:"user_id" db:"user_id"`
+	Rating           int        `json:"rating" db:"rating"`
+	Body             *string    `json:"body" db:"body"`
+	IsVerified       bool       `json:"is_verified" db:"is_verified"`
+	Status           string     `json:"status" db:"status"`
+	ModeratedBy      *int       `json:"moderated_by" db:"moderated_by"`
+	ModeratedAt      *time.Time `json:"moderated_at" db:"moderated_at"`
+	ModerationReason *string    `json:"moderation_reason" db:"moderation_reason"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
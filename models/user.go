@@ -9,6 +9,9 @@ type User struct {
 	Email        string     `json:"email" db:"email"`
 	PasswordHash string     `json:"-" db:"password_hash"`
 	Role         string     `json:"-" db:"role"`
+	IsActive     bool       `json:"-" db:"is_active"`
+	TOTPSecret   *string    `json:"-" db:"totp_secret"`
+	TOTPEnabled  bool       `json:"-" db:"totp_enabled"`
 	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updatedAt" db:"updated_at"`
 	LastLogin    *time.Time `json:"lastLogin,omitempty" db:"last_login"`
@@ -21,7 +24,8 @@ type Profile struct {
 	Email       string     `json:"email" db:"email"`
 	AvatarPath  *string    `json:"avatar_path" db:"avatar_path"`
 	PhoneNumber *string    `json:"phoneNumber" db:"phone_number"`
+	BirthDate   *time.Time `json:"birthDate" db:"birth_date"`
 	CreatedAt   time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt   time.Time  `json:"updatedAt" db:"updated_at"`
 	LastLogin   *time.Time `json:"lastLogin" db:"last_login"`
-}
\ No newline at end of file
+}
@@ -3,13 +3,63 @@ package router
 import (
 	"noir-backend/container"
 	"noir-backend/middleware"
+	"noir-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 func adminRouter(r *gin.RouterGroup, c *container.Container) {
 	r.Use(middleware.AuthMiddleware())
-	r.POST("/movie", c.MovieController.AddMovie)          //add movie by admin
-	r.PATCH("/movie/:id", c.MovieController.UpdateMovie)  //edit movie by admin
-	r.DELETE("/movie/:id", c.MovieController.DeleteMovie) //edit movie by admin
+	r.Use(middleware.RequireRole(utils.RoleAdmin, utils.RoleCinemaManager, utils.RoleUsher, utils.RoleSupport))
+	r.Use(middleware.RequireTwoFactor())
+	r.Use(middleware.CinemaScope(c.AuthService))
+
+	movie := r.Group("/movie", middleware.RequirePermission(utils.PermMovieWrite))
+	movie.POST("", c.MovieController.AddMovie)          //add movie by admin
+	movie.PATCH("/:id", c.MovieController.UpdateMovie)  //edit movie by admin
+	movie.DELETE("/:id", c.MovieController.DeleteMovie) //edit movie by admin
+	movie.POST("/import", c.MovieController.ImportMovies)
+	movie.GET("/export", c.MovieController.ExportMovies)
+	movie.POST("/:id/restore", c.MovieController.RestoreMovie)
+	movie.PUT("/:id/cast", c.MovieController.UpdateCast)
+
+	genres := r.Group("/genres", middleware.RequirePermission(utils.PermMovieWrite))
+	genres.POST("", c.GenreController.CreateGenre)
+	genres.PATCH("/:id", c.GenreController.RenameGenre)
+	genres.POST("/:id/merge", c.GenreController.MergeGenre)
+	genres.DELETE("/:id", c.GenreController.DeleteGenre)
+
+	reviews := r.Group("/reviews", middleware.RequirePermission(utils.PermReviewModerate))
+	reviews.GET("", c.ReviewController.ListReviews)
+	reviews.PATCH("/:id", c.ReviewController.ModerateReview)
+	reviews.DELETE("/:id", c.ReviewController.DeleteReview)
+
+	people := r.Group("/people", middleware.RequirePermission(utils.PermMovieWrite))
+	people.POST("", c.PeopleController.CreatePerson)
+	people.PATCH("/:id", c.PeopleController.UpdatePerson)
+	people.DELETE("/:id", c.PeopleController.DeletePerson)
+
+	showtimes := r.Group("/showtimes", middleware.RequirePermission(utils.PermShowtimeWrite))
+	showtimes.POST("/generate", c.ShowtimeController.GenerateShowtimes)
+
+	transactions := r.Group("/transactions")
+	transactions.GET("", middleware.RequirePermission(utils.PermTransactionRead), c.TransactionController.SearchTransactions)
+	transactions.GET("/:code", middleware.RequirePermission(utils.PermTransactionRead), c.TransactionController.GetTransactionDetail)
+	transactions.POST("/:code/cancel", middleware.RequirePermission(utils.PermTransactionWrite), c.TransactionController.CancelTransaction)
+	transactions.POST("/:code/mark-paid", middleware.RequirePermission(utils.PermTransactionWrite), c.TransactionController.MarkTransactionPaid)
+	transactions.POST("/:code/resend-confirmation", middleware.RequirePermission(utils.PermTransactionWrite), c.TransactionController.ResendConfirmation)
+
+	reports := r.Group("/reports", middleware.RequirePermission(utils.PermReportRead))
+	reports.GET("/dashboard", c.ReportController.Dashboard)
+	reports.GET("/sales", c.ReportController.SalesReport)
+
+	r.GET("/audit-logs", middleware.RequirePermission(utils.PermAuditRead), c.AuditController.ListAuditLogs)
+	r.GET("/cache/stats", middleware.RequirePermission(utils.PermReportRead), c.MovieController.CacheStats)
+
+	users := r.Group("/users")
+	users.GET("", middleware.RequirePermission(utils.PermUserRead), c.UserController.ListUsers)
+	users.GET("/:id", middleware.RequirePermission(utils.PermUserRead), c.UserController.GetUser)
+	users.PATCH("/:id/role", middleware.RequirePermission(utils.PermUserWrite), c.UserController.UpdateRole)
+	users.PATCH("/:id/status", middleware.RequirePermission(utils.PermUserWrite), c.UserController.UpdateStatus)
+	users.POST("/:id/logout", middleware.RequirePermission(utils.PermUserWrite), c.UserController.ForceLogout)
 }
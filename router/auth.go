@@ -10,9 +10,16 @@ import (
 func authRouter(r *gin.RouterGroup, c *container.Container) {
 	r.POST("/register", c.AuthController.Register)
 	r.POST("/login", c.AuthController.Login)
+	r.POST("/login/2fa", c.AuthController.LoginTwoFactor)
+	r.GET("/oidc/login", c.AuthController.OIDCLogin)
+	r.GET("/oidc/callback", c.AuthController.OIDCCallback)
 	r.POST("/forgot-password", c.AuthController.ForgotPassword)
 	r.POST("/reset-password", c.AuthController.ResetPassword)
 
 	r.Use(middleware.AuthMiddleware())
 	r.POST("/logout", c.AuthController.Logout)
+	r.POST("/2fa/enroll", c.AuthController.EnrollTwoFactor)
+	r.POST("/2fa/confirm", c.AuthController.ConfirmTwoFactor)
+	r.POST("/2fa/disable", c.AuthController.DisableTwoFactor)
+	r.POST("/2fa/recovery-codes", c.AuthController.RegenerateRecoveryCodes)
 }
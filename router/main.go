@@ -12,14 +12,19 @@ import (
 
 func CombineRouter(r *gin.Engine, c *container.Container) {
 	docs.SwaggerInfo.BasePath = "/"
+	r.Use(middleware.RequestID())
 	r.Use(middleware.CORS())
 	r.Use(middleware.ErrorHandler())
 	r.Static("/uploads", "./uploads")
 
+	r.GET("/.well-known/jwks.json", c.AuthController.JWKS)
+
 	authRouter(r.Group("/auth"), c)
 	adminRouter(r.Group("/admin"), c)
 	userRouter(r.Group("/profile"), c)
 	movieRouter(r.Group("/movie"), c)
+	searchRouter(r.Group("/search"), c)
+	peopleRouter(r.Group("/people"), c)
 	transactionRouter(r.Group("/transaction"), c)
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
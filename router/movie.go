@@ -3,15 +3,21 @@ package router
 import (
 	"noir-backend/container"
 	"noir-backend/middleware"
+	"noir-backend/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 func movieRouter(r *gin.RouterGroup, c *container.Container) {
-	r.Use(middleware.AuthMiddleware())
+	limit := utils.Load().RateLimit
+	r.Use(middleware.RateLimit("catalog", limit.PublicRequests, limit.Window))
+	r.Use(middleware.OptionalAuth())
 	r.GET("/upcoming-movies", c.MovieController.GetMoviesUpcoming)
 	r.GET("/now-playing-movies", c.MovieController.GetMoviesNowPlaying)
 	r.GET("/", c.MovieController.GetMovies)
 	r.GET("/:id", c.MovieController.GetMovieByID)
-	r.GET("/genres", c.MovieController.GetGenres)
+	r.GET("/genres", c.GenreController.GetGenres)
+	r.GET("/:id/reviews", c.ReviewController.ListMovieReviews)
+	r.POST("/:id/reviews", middleware.AuthMiddleware(), c.ReviewController.SaveReview)
+	r.DELETE("/:id/reviews", middleware.AuthMiddleware(), c.ReviewController.DeleteOwnReview)
 }
@@ -0,0 +1,17 @@
+package router
+
+import (
+	"noir-backend/container"
+	"noir-backend/middleware"
+	"noir-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func peopleRouter(r *gin.RouterGroup, c *container.Container) {
+	limit := utils.Load().RateLimit
+	r.Use(middleware.RateLimit("catalog", limit.PublicRequests, limit.Window))
+	r.Use(middleware.OptionalAuth())
+	r.GET("", c.PeopleController.ListPeople)
+	r.GET("/:id", c.PeopleController.GetPerson)
+}
@@ -0,0 +1,16 @@
+package router
+
+import (
+	"noir-backend/container"
+	"noir-backend/middleware"
+	"noir-backend/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func searchRouter(r *gin.RouterGroup, c *container.Container) {
+	limit := utils.Load().RateLimit
+	r.Use(middleware.RateLimit("search", limit.PublicRequests, limit.Window))
+	r.Use(middleware.OptionalAuth())
+	r.GET("/suggest", c.SearchController.Suggest)
+}
@@ -14,4 +14,8 @@ func transactionRouter(r *gin.RouterGroup, c *container.Container) {
 	r.GET("/:code", c.TransactionController.GetTransaction)
 	r.GET("/", c.TransactionController.GetTransaction)
 
+	r.POST("/group", c.TransactionController.CreateGroupBooking)
+	r.GET("/group/:code", c.TransactionController.GetGroupBooking)
+	r.GET("/group/shares/:code", c.TransactionController.GetGroupShare)
+	r.POST("/group/shares/:code/pay", c.TransactionController.PayGroupShare)
 }
@@ -10,5 +10,9 @@ import (
 func userRouter(r *gin.RouterGroup, c *container.Container) {
 	r.Use(middleware.AuthMiddleware())
 	r.GET("/", c.AuthController.GetProfile)
-	r.PATCH("/") //edit profile
+	r.PATCH("/", c.AuthController.UpdateProfile)
+
+	r.GET("/watchlist", c.WatchlistController.GetWatchlist)
+	r.POST("/watchlist", c.WatchlistController.AddToWatchlist)
+	r.DELETE("/watchlist/:movie_id", c.WatchlistController.RemoveFromWatchlist)
 }
@@ -9,69 +9,28 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func SeedAdminUser(db *pgxpool.Pool) {
-	ctx := context.Background()
-
-	tx, err := db.Begin(context.Background())
-	if err != nil {
-		log.Fatal("failed to begin transaction: ", err)
-	}
-	defer tx.Rollback(context.Background())
-
-	adminEmail := utils.Load().Admin.Email
-	adminPassword := utils.Load().Admin.Password
-
-	var count int
-	query := `
-		SELECT COUNT(*) FROM users
-		WHERE email = $1 AND role = 'admin'`
-
-	err = tx.QueryRow(ctx, query, adminEmail).Scan(&count)
+// SeedAdminUser creates the admin configured by ADMIN_EMAIL and
+// ADMIN_PASSWORD unless it already exists.
+func SeedAdminUser(ctx context.Context, db *pgxpool.Pool) error {
+	admin := utils.Load().Admin
+
+	var exists bool
+	err := db.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND role = 'admin')`,
+		admin.Email).Scan(&exists)
 	if err != nil {
-		log.Fatal("failed to check admin existence: ", err)
+		return fmt.Errorf("failed to check admin existence: %w", err)
 	}
 
-	if count > 0 {
-		log.Printf("Admin '%s' already exists", adminEmail)
-		return
+	if exists {
+		log.Printf("Admin '%s' already exists", admin.Email)
+		return nil
 	}
 
-	hashedPassword, err := utils.HashPassword(adminPassword)
-	if err != nil {
-		log.Fatal("failed to hash password: ", err)
-	}
-
-	var userID int
-
-	err = tx.QueryRow(context.Background(), `
-		INSERT INTO users (email, password, role)
-		VALUES ($1, $2, 'admin')
-		RETURNING user_id`,
-		adminEmail, hashedPassword).
-		Scan(&userID)
-	if err != nil {
-		log.Fatal("failed to create admin user: ", err)
-	}
-
-	result, err := tx.Exec(context.Background(), `
-		INSERT INTO profile (user_id) 
-		VALUES ($1)
-		RETURNING user_id`,
-		userID)
-	if err != nil {
-		log.Fatal("failed to create admin user: ", err)
-	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		log.Fatal("no rows were inserted")
-	}
-
-	err = tx.Commit(ctx)
-	if err != nil {
-		log.Fatal("failed to commit transaction: ", err)
+	if _, err := CreateUser(ctx, db, admin.Email, admin.Password, utils.RoleAdmin); err != nil {
+		return fmt.Errorf("failed to create admin user: %w", err)
 	}
 
-	log.Printf("Admin '%s' created successfully", adminEmail)
-	fmt.Println(userID)
+	log.Printf("Admin '%s' created successfully", admin.Email)
+	return nil
 }
@@ -2,163 +2,232 @@ package seeder
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"noir-backend/utils"
-	"os"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/joho/godotenv"
 )
 
-type MovieListResponse struct {
-	Results []struct {
-		ID int `json:"id"`
-	} `json:"results"`
-}
-
-type MovieDetails struct {
-	Title        string `json:"title"`
-	Overview     string `json:"overview"`
-	ReleaseDate  string `json:"release_date"`
-	Runtime      int    `json:"runtime"`
-	PosterPath   string `json:"poster_path"`
-	BackdropPath string `json:"backdrop_path"`
-	Genres       []struct {
-		Name string `json:"name"`
-	} `json:"genres"`
-}
+// maxImportedCast is how many top-billed actors are kept per movie.
+const maxImportedCast = 10
 
-type Credits struct {
-	Crew []struct {
-		Name string `json:"name"`
-		Job  string `json:"job"`
-	} `json:"crew"`
-	Cast []struct {
-		Name string `json:"name"`
-		Role string `json:"character"`
-	} `json:"cast"`
+type ImportResult struct {
+	Created int
+	Updated int
+	Skipped int
 }
 
-func fetchJSON(url string, target interface{}) error {
-	resp, err := http.Get(url)
+// ImportTMDBMovies loads TMDB fixtures from path and upserts them. Movies,
+// people and genres are matched by their TMDB id, so running it again only
+// refreshes what changed. A movie that fails to import is logged and skipped.
+func ImportTMDBMovies(ctx context.Context, db *pgxpool.Pool, path string) (*ImportResult, error) {
+	movies, err := LoadTMDBFixtures(path)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
-	return json.NewDecoder(resp.Body).Decode(target)
-}
 
-func SeedTMDBMovies(db *pgxpool.Pool) error {
-	if err := seedFromTMDBEndpoint(db, "now_playing"); err != nil {
-		return err
-	}
-	if err := seedFromTMDBEndpoint(db, "upcoming"); err != nil {
-		return err
+	imageBaseURL := utils.Load().TMDB.ImageBaseURL
+	result := &ImportResult{}
+	for _, movie := range movies {
+		created, err := importTMDBMovie(ctx, db, movie, imageBaseURL)
+		if err != nil {
+			log.Printf("failed to import movie %d (%s): %v", movie.ID, movie.Title, err)
+			result.Skipped++
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
 	}
-	return nil
-}
-
-func seedFromTMDBEndpoint(db *pgxpool.Pool, category string) error {
-	godotenv.Load()
-	API_KEY := os.Getenv("TMDB_API_KEY")
 
-	url := fmt.Sprintf("https://api.themoviedb.org/3/movie/%s?api_key=%s&language=en-US&page=1", category, API_KEY)
+	return result, nil
+}
 
-	var result MovieListResponse
-	if err := fetchJSON(url, &result); err != nil {
-		return fmt.Errorf("failed to fetch %s: %v", category, err)
+func importTMDBMovie(ctx context.Context, db *pgxpool.Pool, movie TMDBMovie, imageBaseURL string) (bool, error) {
+	if movie.ID == 0 || movie.Title == "" {
+		return false, fmt.Errorf("missing id or title")
+	}
+	releaseDate, err := time.Parse("2006-01-02", movie.ReleaseDate)
+	if err != nil {
+		return false, fmt.Errorf("invalid release date %q", movie.ReleaseDate)
 	}
 
-	ctx := context.Background()
 	tx, err := db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %v", err)
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	for _, m := range result.Results {
-		var detail MovieDetails
-		var credits Credits
-
-		detailURL := fmt.Sprintf("https://api.themoviedb.org/3/movie/%d?api_key=%s", m.ID, API_KEY)
-		creditURL := fmt.Sprintf("https://api.themoviedb.org/3/movie/%d/credits?api_key=%s", m.ID, API_KEY)
-
-		if err := fetchJSON(detailURL, &detail); err != nil {
-			log.Printf("failed to fetch movie details for ID %d: %v", m.ID, err)
+	var directorID *int
+	for _, crew := range movie.Credits.Crew {
+		if crew.Job != "Director" {
 			continue
 		}
-
-		if err := fetchJSON(creditURL, &credits); err != nil {
-			log.Printf("failed to fetch movie credits for ID %d: %v", m.ID, err)
-			continue
+		personID, err := upsertTMDBPerson(ctx, tx, crew.ID, crew.Name)
+		if err != nil {
+			return false, fmt.Errorf("failed to import director: %w", err)
 		}
-
-		var directorID int
-		for _, crew := range credits.Crew {
-			if crew.Job == "Director" {
-				firstName, lastName := utils.SplitFullName(crew.Name)
-				tx.QueryRow(context.Background(), `
-						INSERT INTO directors (first_name, last_name) VALUES ($1, $2)
-						ON CONFLICT (first_name, last_name) DO NOTHING
-						RETURNING id
-					`, firstName, lastName).Scan(&directorID)
-				break
-			}
+		var id int
+		err = tx.QueryRow(ctx, `
+			INSERT INTO directors (first_name, last_name, person_id)
+			SELECT first_name, last_name, id FROM people WHERE id = $1
+			ON CONFLICT (person_id) DO UPDATE SET person_id = EXCLUDED.person_id
+			RETURNING id`,
+			personID).Scan(&id)
+		if err != nil {
+			return false, fmt.Errorf("failed to import director: %w", err)
 		}
+		directorID = &id
+		break
+	}
+
+	var movieID int
+	var created bool
+	err = tx.QueryRow(ctx, `
+		INSERT INTO movies (tmdb_id, title, overview, duration, release_date, director_id, poster_path, backdrop_path, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		ON CONFLICT (tmdb_id) DO UPDATE SET
+			title = EXCLUDED.title,
+			overview = EXCLUDED.overview,
+			duration = EXCLUDED.duration,
+			release_date = EXCLUDED.release_date,
+			director_id = EXCLUDED.director_id,
+			poster_path = EXCLUDED.poster_path,
+			backdrop_path = EXCLUDED.backdrop_path,
+			updated_at = NOW()
+		RETURNING movie_id, (xmax = 0)`,
+		movie.ID, movie.Title, movie.Overview, movie.Runtime, releaseDate, directorID,
+		tmdbImageURL(imageBaseURL, "w500", movie.PosterPath),
+		tmdbImageURL(imageBaseURL, "original", movie.BackdropPath),
+	).Scan(&movieID, &created)
+	if err != nil {
+		return false, fmt.Errorf("failed to upsert movie: %w", err)
+	}
 
-		releaseDate, err := time.Parse("2006-01-02", detail.ReleaseDate)
+	_, err = tx.Exec(ctx, "DELETE FROM movies_genres WHERE movie_id = $1", movieID)
+	if err != nil {
+		return false, fmt.Errorf("failed to reset genres: %w", err)
+	}
+	for _, genre := range movie.Genres {
+		genreID, err := upsertTMDBGenre(ctx, tx, genre)
 		if err != nil {
-			continue
+			return false, fmt.Errorf("failed to import genre %s: %w", genre.Name, err)
 		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO movies_genres (movie_id, genre_id) VALUES ($1, $2)
+			ON CONFLICT (movie_id, genre_id) DO NOTHING`,
+			movieID, genreID)
+		if err != nil {
+			return false, fmt.Errorf("failed to add genre: %w", err)
+		}
+	}
 
-		var movieID int
-		err = tx.QueryRow(context.Background(), `
-			INSERT INTO movies (title, overview, duration, release_date, directors_id, poster_path, backdrop_path, created_at, updated_at)
-		 	VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
-			RETURNING id
-		`, detail.Title, detail.Overview, detail.Runtime, releaseDate, directorID,
-			"https://image.tmdb.org/t/p/w500"+detail.PosterPath,
-			"https://image.tmdb.org/t/p/original"+detail.BackdropPath,
-		).Scan(&movieID)
-
+	_, err = tx.Exec(ctx, "DELETE FROM movies_cast WHERE movie_id = $1", movieID)
+	if err != nil {
+		return false, fmt.Errorf("failed to reset cast: %w", err)
+	}
+	for i, cast := range movie.Credits.Cast {
+		if i >= maxImportedCast {
+			break
+		}
+		personID, err := upsertTMDBPerson(ctx, tx, cast.ID, cast.Name)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Insert failed for %s: %v\n", detail.Title, err)
+			return false, fmt.Errorf("failed to import actor %s: %w", cast.Name, err)
 		}
 
-		for _, g := range detail.Genres {
-			var genreID int
-			tx.QueryRow(context.Background(), `
-					INSERT INTO genres (genre_name) VALUES ($1)
-					ON CONFLICT (genre_name) DO NOTHING
-					RETURNING id
-				`, g.Name).Scan(&genreID)
+		var actorID int
+		err = tx.QueryRow(ctx, `
+			INSERT INTO actors (first_name, last_name, person_id)
+			SELECT first_name, last_name, id FROM people WHERE id = $1
+			ON CONFLICT (person_id) DO UPDATE SET person_id = EXCLUDED.person_id
+			RETURNING id`,
+			personID).Scan(&actorID)
+		if err != nil {
+			return false, fmt.Errorf("failed to import actor %s: %w", cast.Name, err)
+		}
 
-			tx.Exec(context.Background(), `INSERT INTO movie_genres (movie_id, genre_id) VALUES ($1, $2)`, movieID, genreID)
+		var character *string
+		if cast.Character != "" {
+			character = &cast.Character
 		}
-		for i, cast := range credits.Cast {
-			if i >= 5 {
-				break
-			}
+		_, err = tx.Exec(ctx,
+			"INSERT INTO movies_cast (movie_id, actor_id, character_name, billing_order) VALUES ($1, $2, $3, $4)",
+			movieID, actorID, character, cast.Order+1)
+		if err != nil {
+			return false, fmt.Errorf("failed to add cast: %w", err)
+		}
+	}
 
-			firstName, lastName := utils.SplitFullName(cast.Name)
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
-			var actorID int
-			tx.QueryRow(context.Background(), `
-					INSERT INTO actors (first_name, last_name) VALUES ($1, $2)
-					ON CONFLICT (first_name, last_name) DO NOTHING
-					RETURNING id
-				`, firstName, lastName).Scan(&actorID)
+	return created, nil
+}
 
-			tx.Exec(context.Background(), `INSERT INTO movie_casts (movie_id, actor_id, role) VALUES ($1, $2)`, movieID, actorID, cast.Role)
-		}
+// upsertTMDBPerson finds a person by TMDB id. People added by hand before the
+// import are adopted by name instead of being duplicated.
+func upsertTMDBPerson(ctx context.Context, tx pgx.Tx, tmdbID int, fullName string) (int, error) {
+	firstName, lastName := utils.SplitFullName(fullName)
+
+	var personID int
+	err := tx.QueryRow(ctx, "SELECT id FROM people WHERE tmdb_id = $1", tmdbID).Scan(&personID)
+	if err == nil {
+		return personID, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
 	}
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %v", err)
+
+	err = tx.QueryRow(ctx, `
+		UPDATE people SET tmdb_id = $3, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM people
+			WHERE first_name = $1 AND last_name = $2 AND tmdb_id IS NULL
+			ORDER BY id LIMIT 1
+		)
+		RETURNING id`,
+		firstName, lastName, tmdbID).Scan(&personID)
+	if err == nil {
+		return personID, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
 	}
 
-	return nil
+	err = tx.QueryRow(ctx,
+		"INSERT INTO people (first_name, last_name, tmdb_id) VALUES ($1, $2, $3) RETURNING id",
+		firstName, lastName, tmdbID).Scan(&personID)
+	return personID, err
+}
+
+// upsertTMDBGenre finds a genre by TMDB id, falling back to its name so the
+// genres seeded by migrations are reused.
+func upsertTMDBGenre(ctx context.Context, tx pgx.Tx, genre TMDBGenre) (int, error) {
+	var genreID int
+	err := tx.QueryRow(ctx, "SELECT id FROM genres WHERE tmdb_id = $1", genre.ID).Scan(&genreID)
+	if err == nil {
+		return genreID, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO genres (name, tmdb_id) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET tmdb_id = COALESCE(genres.tmdb_id, EXCLUDED.tmdb_id)
+		RETURNING id`,
+		genre.Name, genre.ID).Scan(&genreID)
+	return genreID, err
+}
+
+func tmdbImageURL(baseURL, size, path string) *string {
+	if path == "" {
+		return nil
+	}
+	url := fmt.Sprintf("%s/%s%s", baseURL, size, path)
+	return &url
 }
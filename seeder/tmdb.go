@@ -0,0 +1,201 @@
+package seeder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	neturl "net/url"
+	"noir-backend/utils"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// TMDBMovie is the shape of TMDB's /movie/{id}?append_to_response=credits
+// response, trimmed to the fields the catalog uses.
+type TMDBMovie struct {
+	ID           int         `json:"id"`
+	Title        string      `json:"title"`
+	Overview     string      `json:"overview"`
+	ReleaseDate  string      `json:"release_date"`
+	Runtime      int         `json:"runtime"`
+	PosterPath   string      `json:"poster_path"`
+	BackdropPath string      `json:"backdrop_path"`
+	Genres       []TMDBGenre `json:"genres"`
+	Credits      TMDBCredits `json:"credits"`
+}
+
+type TMDBGenre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type TMDBCredits struct {
+	Cast []TMDBCast `json:"cast"`
+	Crew []TMDBCrew `json:"crew"`
+}
+
+type TMDBCast struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Character string `json:"character"`
+	Order     int    `json:"order"`
+}
+
+type TMDBCrew struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Job  string `json:"job"`
+}
+
+// TMDBDump is the file written by FetchTMDBFixtures.
+type TMDBDump struct {
+	FetchedAt time.Time   `json:"fetched_at"`
+	Movies    []TMDBMovie `json:"movies"`
+}
+
+type tmdbMovieList struct {
+	Results []struct {
+		ID int `json:"id"`
+	} `json:"results"`
+}
+
+var tmdbHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// LoadTMDBFixtures reads movies from a file or from every .json file in a
+// directory. A file may hold a TMDBDump, a list of movies or a single movie.
+func LoadTMDBFixtures(path string) ([]TMDBMovie, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	}
+
+	movies := []TMDBMovie{}
+	for _, file := range files {
+		loaded, err := readTMDBFixture(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		movies = append(movies, loaded...)
+	}
+
+	return movies, nil
+}
+
+func readTMDBFixture(file string) ([]TMDBMovie, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var movies []TMDBMovie
+		err := json.Unmarshal(data, &movies)
+		return movies, err
+	}
+
+	var dump TMDBDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, err
+	}
+	if dump.Movies != nil {
+		return dump.Movies, nil
+	}
+
+	var movie TMDBMovie
+	if err := json.Unmarshal(data, &movie); err != nil {
+		return nil, err
+	}
+	if movie.ID == 0 {
+		return nil, fmt.Errorf("no movies found")
+	}
+	return []TMDBMovie{movie}, nil
+}
+
+// FetchTMDBFixtures downloads the movies of the given TMDB lists (e.g.
+// now_playing, upcoming) with their credits and writes them to outPath as a
+// TMDBDump. It needs network access and TMDB_API_KEY.
+func FetchTMDBFixtures(ctx context.Context, categories []string, outPath string) (int, error) {
+	cfg := utils.Load().TMDB
+	if cfg.APIKey == "" {
+		return 0, fmt.Errorf("TMDB_API_KEY is not set")
+	}
+
+	seen := map[int]bool{}
+	dump := TMDBDump{FetchedAt: time.Now().UTC(), Movies: []TMDBMovie{}}
+	for _, category := range categories {
+		var list tmdbMovieList
+		url := fmt.Sprintf("%s/movie/%s?api_key=%s&language=en-US&page=1", cfg.BaseURL, category, cfg.APIKey)
+		if err := fetchTMDB(ctx, url, &list); err != nil {
+			return 0, fmt.Errorf("failed to fetch %s: %w", category, err)
+		}
+
+		for _, result := range list.Results {
+			if seen[result.ID] {
+				continue
+			}
+			seen[result.ID] = true
+
+			var movie TMDBMovie
+			url := fmt.Sprintf("%s/movie/%d?api_key=%s&language=en-US&append_to_response=credits", cfg.BaseURL, result.ID, cfg.APIKey)
+			if err := fetchTMDB(ctx, url, &movie); err != nil {
+				log.Printf("failed to fetch movie %d: %v", result.ID, err)
+				continue
+			}
+			dump.Movies = append(dump.Movies, movie)
+		}
+	}
+
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if dir := filepath.Dir(outPath); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return 0, err
+		}
+	}
+	if err := os.WriteFile(outPath, append(data, '\n'), 0o644); err != nil {
+		return 0, err
+	}
+
+	return len(dump.Movies), nil
+}
+
+func fetchTMDB(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := tmdbHTTPClient.Do(req)
+	if err != nil {
+		// The request URL carries the API key, keep it out of logs.
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
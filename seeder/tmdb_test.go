@@ -0,0 +1,123 @@
+package seeder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTMDBFixturesBundledDump(t *testing.T) {
+	movies, err := LoadTMDBFixtures(filepath.Join("fixtures", "tmdb_movies.json"))
+	if err != nil {
+		t.Fatalf("LoadTMDBFixtures: %v", err)
+	}
+	if len(movies) == 0 {
+		t.Fatal("bundled fixture has no movies")
+	}
+
+	seen := map[int]bool{}
+	for _, movie := range movies {
+		if movie.ID == 0 || movie.Title == "" {
+			t.Errorf("movie without id or title: %+v", movie)
+		}
+		if seen[movie.ID] {
+			t.Errorf("movie %d appears more than once", movie.ID)
+		}
+		seen[movie.ID] = true
+		if movie.Runtime <= 0 {
+			t.Errorf("%s has no runtime", movie.Title)
+		}
+		if len(movie.Genres) == 0 {
+			t.Errorf("%s has no genres", movie.Title)
+		}
+		if len(movie.Credits.Cast) == 0 || len(movie.Credits.Crew) == 0 {
+			t.Errorf("%s has no credits", movie.Title)
+		}
+	}
+
+	if movies[0].Title != "Inception" || movies[0].ReleaseDate != "2010-07-15" {
+		t.Errorf("first movie = %s (%s), want Inception (2010-07-15)", movies[0].Title, movies[0].ReleaseDate)
+	}
+}
+
+func TestLoadTMDBFixturesFormats(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		wantIDs   []int
+		wantError bool
+	}{
+		{
+			name:    "dump",
+			files:   map[string]string{"dump.json": `{"fetched_at":"2025-06-01T00:00:00Z","movies":[{"id":1,"title":"A"},{"id":2,"title":"B"}]}`},
+			wantIDs: []int{1, 2},
+		},
+		{
+			name:    "list",
+			files:   map[string]string{"list.json": ` [{"id":3,"title":"C"}]`},
+			wantIDs: []int{3},
+		},
+		{
+			name:    "single movie",
+			files:   map[string]string{"movie.json": `{"id":4,"title":"D"}`},
+			wantIDs: []int{4},
+		},
+		{
+			name: "directory read in name order",
+			files: map[string]string{
+				"b.json":    `{"id":6,"title":"F"}`,
+				"a.json":    `[{"id":5,"title":"E"}]`,
+				"notes.txt": `ignored`,
+			},
+			wantIDs: []int{5, 6},
+		},
+		{
+			name:      "no movies",
+			files:     map[string]string{"empty.json": `{}`},
+			wantError: true,
+		},
+		{
+			name:      "malformed json",
+			files:     map[string]string{"broken.json": `{"movies": [`},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+					t.Fatalf("write %s: %v", name, err)
+				}
+			}
+
+			path := dir
+			if len(tt.files) == 1 {
+				for name := range tt.files {
+					path = filepath.Join(dir, name)
+				}
+			}
+
+			movies, err := LoadTMDBFixtures(path)
+			if tt.wantError {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", movies)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTMDBFixtures: %v", err)
+			}
+
+			if len(movies) != len(tt.wantIDs) {
+				t.Fatalf("got %d movies, want %d", len(movies), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if movies[i].ID != id {
+					t.Errorf("movie %d has id %d, want %d", i, movies[i].ID, id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package seeder
+
+import (
+	"context"
+	"fmt"
+	"noir-backend/utils"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CreateUser adds an account with the given role and an empty profile.
+func CreateUser(ctx context.Context, db *pgxpool.Pool, email, password, role string) (int, error) {
+	if !utils.IsValidRole(role) {
+		return 0, fmt.Errorf("invalid role: %s", role)
+	}
+	if email == "" || password == "" {
+		return 0, fmt.Errorf("email and password are required")
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)", email).Scan(&exists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check user existence: %w", err)
+	}
+	if exists {
+		return 0, fmt.Errorf("user %s already exists", email)
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return 0, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	var userID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (email, password_hash, role)
+		VALUES ($1, $2, $3)
+		RETURNING user_id`,
+		email, hashedPassword, role).Scan(&userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "INSERT INTO profile (user_id) VALUES ($1)", userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create profile: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"noir-backend/utils"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// recordAudit appends an entry to audit_log. Pass the surrounding pgx.Tx so the
+// entry is only written when the audited change is committed. The request ID
+// and client IP are taken from ctx when the entry does not set them.
+func recordAudit(ctx context.Context, db execer, entry models.AuditLog) error {
+	info := utils.RequestInfoFrom(ctx)
+	if entry.IPAddress == nil && info.IP != "" {
+		entry.IPAddress = &info.IP
+	}
+	if entry.RequestID == nil && info.ID != "" {
+		entry.RequestID = &info.ID
+	}
+	if len(entry.Changes) == 0 {
+		entry.Changes = nil
+	}
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO audit_log (actor_id, action, entity_type, entity_id, metadata, changes, ip_address, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		entry.ActorID, entry.Action, entry.EntityType, entry.EntityID, entry.Metadata,
+		entry.Changes, entry.IPAddress, entry.RequestID)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// auditDiff returns the fields whose value differs between before and after,
+// compared by their JSON encoding. Fields missing on one side count as null.
+func auditDiff(before, after map[string]any) map[string]models.AuditChange {
+	changes := map[string]models.AuditChange{}
+	for key, value := range after {
+		if !sameJSON(before[key], value) {
+			changes[key] = models.AuditChange{Before: before[key], After: value}
+		}
+	}
+	for key, value := range before {
+		if _, ok := after[key]; !ok && value != nil {
+			changes[key] = models.AuditChange{Before: value}
+		}
+	}
+	return changes
+}
+
+func sameJSON(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+type AuditService struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditService(db *pgxpool.Pool) *AuditService {
+	return &AuditService{db: db}
+}
+
+// ListAuditLogs returns audit entries newest first. An action ending with a
+// dot, e.g. "movie.", matches every action of that family.
+func (s *AuditService) ListAuditLogs(ctx context.Context, filter dto.AuditLogFilter, limit, offset int) ([]models.AuditLog, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if filter.ActorID != 0 {
+		args = append(args, filter.ActorID)
+		conditions = append(conditions, fmt.Sprintf("actor_id = $%d", len(args)))
+	}
+	if filter.Action != "" {
+		if strings.HasSuffix(filter.Action, ".") {
+			args = append(args, filter.Action+"%")
+			conditions = append(conditions, fmt.Sprintf("action LIKE $%d", len(args)))
+		} else {
+			args = append(args, filter.Action)
+			conditions = append(conditions, fmt.Sprintf("action = $%d", len(args)))
+		}
+	}
+	if filter.EntityType != "" {
+		args = append(args, filter.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = $%d", len(args)))
+	}
+	if filter.EntityID != "" {
+		args = append(args, filter.EntityID)
+		conditions = append(conditions, fmt.Sprintf("entity_id = $%d", len(args)))
+	}
+	if filter.RequestID != "" {
+		args = append(args, filter.RequestID)
+		conditions = append(conditions, fmt.Sprintf("request_id = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := s.db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) FROM audit_log %s", where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`
+		SELECT id, actor_id, action, entity_type, entity_id, metadata, changes, ip_address, request_id, created_at
+		FROM audit_log
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2),
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	logs, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AuditLog])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect audit logs: %w", err)
+	}
+
+	return logs, total, nil
+}
@@ -1,16 +1,13 @@
 package services
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
-	"net/smtp"
 	"noir-backend/dto"
 	"noir-backend/models"
-	"text/template"
 	"time"
 
 	"noir-backend/utils"
@@ -26,7 +23,7 @@ type AuthService struct {
 }
 
 func NewAuthService(db *pgxpool.Pool, redis *redis.Client) *AuthService {
-	return &AuthService{db: db}
+	return &AuthService{db: db, redis: redis}
 }
 
 func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*dto.UserResponse, error) {
@@ -91,9 +88,9 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
 	user := &models.User{}
 	err := s.db.QueryRow(ctx,
-		`SELECT user_id, email, password_hash, role, created_at, updated_at, last_login
+		`SELECT user_id, email, password_hash, role, is_active, totp_enabled, created_at, updated_at, last_login
 		FROM users WHERE email = $1`,
-		req.Email).Scan(&user.UserID, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.LastLogin)
+		req.Email).Scan(&user.UserID, &user.Email, &user.PasswordHash, &user.Role, &user.IsActive, &user.TOTPEnabled, &user.CreatedAt, &user.UpdatedAt, &user.LastLogin)
 	if err != nil {
 		fmt.Println(err)
 		return nil, errors.New("invalid credentials")
@@ -103,7 +100,27 @@ func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 		return nil, errors.New("invalid credentials")
 	}
 
-	token, err := utils.GenerateTokens(user.UserID, user.Role)
+	if !user.IsActive {
+		return nil, errors.New("account disabled")
+	}
+
+	if user.TOTPEnabled {
+		challenge, err := s.createTwoFactorChallenge(ctx, user.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &dto.AuthResponse{
+			RequiresTwoFactor: true,
+			ChallengeToken:    challenge,
+		}, nil
+	}
+
+	return s.completeLogin(ctx, user, false)
+}
+
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User, mfa bool) (*dto.AuthResponse, error) {
+	token, err := utils.GenerateTokens(user.UserID, user.Role, mfa)
 	if err != nil {
 		return nil, err
 	}
@@ -121,19 +138,20 @@ func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 	}
 
 	return &dto.AuthResponse{
-		User:  userReponse,
-		Token: token,
+		User:                   userReponse,
+		Token:                  token,
+		TwoFactorSetupRequired: !mfa && user.Role == utils.RoleAdmin && utils.Load().TwoFactor.RequiredForAdmin,
 	}, nil
 }
 
 func (s *AuthService) GetUserByID(ctx context.Context, userID int) (*models.Profile, error) {
 	var user models.Profile
 	err := s.db.QueryRow(ctx, `
-		SELECT profile_id, first_name, last_name, email, phone_number, p.created_at, p.updated_at, last_login
+		SELECT profile_id, first_name, last_name, email, phone_number, birth_date, p.created_at, p.updated_at, last_login
 		FROM profile p
 		JOIN users u ON u.user_id = p.user_id
 		WHERE p.user_id = $1`,
-		userID).Scan(&user.UserID, &user.FirstName, &user.LastName, &user.Email, &user.PhoneNumber, &user.CreatedAt, &user.UpdatedAt, &user.LastLogin)
+		userID).Scan(&user.UserID, &user.FirstName, &user.LastName, &user.Email, &user.PhoneNumber, &user.BirthDate, &user.CreatedAt, &user.UpdatedAt, &user.LastLogin)
 	if err != nil {
 		fmt.Println(err)
 		return nil, err
@@ -141,12 +159,88 @@ func (s *AuthService) GetUserByID(ctx context.Context, userID int) (*models.Prof
 	return &user, nil
 }
 
+// UpdateProfile edits the fields present in req and returns the saved profile.
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int, req dto.UpdateProfileRequest) (*models.Profile, int, error) {
+	var birthDate *time.Time
+	if req.BirthDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.BirthDate)
+		if err != nil {
+			return nil, http.StatusBadRequest, errors.New("birth_date must use YYYY-MM-DD format")
+		}
+		if parsed.After(time.Now()) {
+			return nil, http.StatusBadRequest, errors.New("birth_date cannot be in the future")
+		}
+		birthDate = &parsed
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	// The birth date backs the age check at booking, so once set it is not
+	// the user's to change.
+	var currentBirthDate *time.Time
+	err = tx.QueryRow(ctx,
+		"SELECT birth_date FROM profile WHERE user_id = $1 FOR UPDATE", userID).Scan(&currentBirthDate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("profile not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get profile: %w", err)
+	}
+	if birthDate != nil && currentBirthDate != nil && !currentBirthDate.Equal(*birthDate) {
+		return nil, http.StatusConflict, errors.New("birth_date is already set, contact support to correct it")
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE profile SET
+			first_name = COALESCE($2, first_name),
+			last_name = COALESCE($3, last_name),
+			phone_number = COALESCE($4, phone_number),
+			birth_date = COALESCE($5, birth_date),
+			updated_at = NOW()
+		WHERE user_id = $1`,
+		userID, trimmed(req.FirstName), trimmed(req.LastName), trimmed(req.PhoneNumber), birthDate)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update profile: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, http.StatusNotFound, errors.New("profile not found")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	profile, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return profile, http.StatusOK, nil
+}
+
 func (r *AuthService) UpdateLastLogin(ctx context.Context, userID *int) error {
 	query := `UPDATE users SET last_login = $1 WHERE user_id = $2`
 	_, err := r.db.Exec(ctx, query, time.Now(), userID)
 	return err
 }
 
+func (s *AuthService) GetManagedCinemaIDs(ctx context.Context, userID int) ([]int, error) {
+	rows, err := s.db.Query(ctx,
+		"SELECT cinema_id FROM cinema_managers WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed cinemas: %w", err)
+	}
+
+	cinemaIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect managed cinemas: %w", err)
+	}
+
+	return cinemaIDs, nil
+}
+
 func (r *AuthService) Logout(token string) error {
 	return r.redis.Set(context.Background(), fmt.Sprintf("blacklist-token:%s", token), "1", 24*time.Hour).Err()
 }
@@ -161,7 +255,7 @@ func (s *AuthService) ForgotPassword(ctx context.Context, email string) (string,
 	} else if err != nil {
 		return "", fmt.Errorf("database error: %w", err)
 	}
-	token, err := utils.GenerateTokens(userID, "user")
+	token, err := utils.GenerateTokens(userID, "user", false)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate token reset: %w", err)
 	}
@@ -219,34 +313,11 @@ func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordRe
 }
 
 func sendResetEmail(email, token string) error {
-	resetURL := fmt.Sprintf("http://localhost:8080/reset-password?token=%s", token)
-	subject := "Password Reset Request"
-	body, err := buildResetEmailBody(resetURL)
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", utils.Load().AppURL, token)
+	body, err := utils.RenderMailTemplate("reset_password_email.txt", struct{ ResetURL string }{ResetURL: resetURL})
 	if err != nil {
 		return err
 	}
 
-	msg := fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s", email, subject, body)
-
-	config := utils.Load().SMTP
-
-	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
-
-	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	return smtp.SendMail(addr, auth, config.From, []string{email}, []byte(msg))
-}
-
-func buildResetEmailBody(resetURL string) (string, error) {
-	tmpl, err := template.ParseFiles("templates/reset_password_email.txt")
-	if err != nil {
-		return "", fmt.Errorf("error parsing file: %v", err)
-	}
-
-	var body bytes.Buffer
-	err = tmpl.Execute(&body, struct{ ResetURL string }{ResetURL: resetURL})
-	if err != nil {
-		return "", fmt.Errorf("error execute file: %v", err)
-	}
-
-	return body.String(), nil
+	return utils.SendMail(email, "Password Reset Request", body)
 }
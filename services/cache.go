@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+type cacheCounter struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type CacheStats struct {
+	Name    string  `json:"name"`
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// Cache is a read-through cache on top of Redis. Keys live in namespaces whose
+// version is bumped to invalidate every key at once.
+type Cache struct {
+	redis    *redis.Client
+	group    singleflight.Group
+	mu       sync.Mutex
+	counters map[string]*cacheCounter
+}
+
+func NewCache(redis *redis.Client) *Cache {
+	return &Cache{redis: redis, counters: map[string]*cacheCounter{}}
+}
+
+func (c *Cache) counter(name string) *cacheCounter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter, ok := c.counters[name]
+	if !ok {
+		counter = &cacheCounter{}
+		c.counters[name] = counter
+	}
+	return counter
+}
+
+func (c *Cache) namespaceVersion(ctx context.Context, namespace string) (int64, error) {
+	version, err := c.redis.Get(ctx, fmt.Sprintf("%s:version", namespace)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return version, err
+}
+
+// Invalidate drops every key of the namespace by moving it to a new version.
+// Entries of the old version are left to expire on their own.
+func (c *Cache) Invalidate(ctx context.Context, namespace string) {
+	if err := c.redis.Incr(ctx, fmt.Sprintf("%s:version", namespace)).Err(); err != nil {
+		log.Printf("failed to invalidate cache namespace %s: %v", namespace, err)
+	}
+}
+
+func (c *Cache) Stats() []CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make([]CacheStats, 0, len(c.counters))
+	for name, counter := range c.counters {
+		hits, misses := counter.hits.Load(), counter.misses.Load()
+		stat := CacheStats{Name: name, Hits: hits, Misses: misses}
+		if hits+misses > 0 {
+			stat.HitRate = float64(hits) / float64(hits+misses)
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// cached returns the value stored under namespace/name/key or calls load and
+// stores its result for ttl. Concurrent misses on the same key share a single
+// load, and ttl gets a small jitter so hot keys do not all expire together.
+// Redis failures fall back to load.
+func cached[T any](ctx context.Context, c *Cache, namespace, name, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	counter := c.counter(name)
+
+	version, err := c.namespaceVersion(ctx, namespace)
+	if err != nil {
+		log.Printf("cache unavailable: %v", err)
+		counter.misses.Add(1)
+		return load()
+	}
+	fullKey := fmt.Sprintf("%s:v%d:%s:%s", namespace, version, name, key)
+
+	var value T
+	if data, err := c.redis.Get(ctx, fullKey).Bytes(); err == nil {
+		if err := json.Unmarshal(data, &value); err == nil {
+			counter.hits.Add(1)
+			return value, nil
+		}
+	}
+	counter.misses.Add(1)
+
+	result, err, _ := c.group.Do(fullKey, func() (any, error) {
+		loaded, err := load()
+		if err != nil {
+			return loaded, err
+		}
+
+		if data, err := json.Marshal(loaded); err == nil {
+			jitter := time.Duration(rand.Int64N(int64(ttl / 10)))
+			if err := c.redis.Set(ctx, fullKey, data, ttl+jitter).Err(); err != nil {
+				log.Printf("failed to write cache key %s: %v", fullKey, err)
+			}
+		}
+		return loaded, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	return result.(T), nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"noir-backend/dto"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	dashboardCacheNamespace = "dashboard"
+	// dashboardCacheTTL is short because pending transactions only live for a
+	// few minutes.
+	dashboardCacheTTL       = 30 * time.Second
+	dashboardExpiryWindow   = 2 * time.Minute
+	dashboardUpcomingWindow = 7 * 24 * time.Hour
+	dashboardFillLimit      = 5
+)
+
+// Dashboard returns the back-office KPIs. cinemaIDs restricts the sales and
+// showtime figures to a cinema manager's cinemas, nil means every cinema.
+func (s *ReportService) Dashboard(ctx context.Context, cinemaIDs []int) (*dto.DashboardSummary, error) {
+	key := "all"
+	if cinemaIDs != nil {
+		ids := append([]int{}, cinemaIDs...)
+		sort.Ints(ids)
+		parts := make([]string, len(ids))
+		for i, id := range ids {
+			parts[i] = strconv.Itoa(id)
+		}
+		key = "cinemas:" + strings.Join(parts, ",")
+	}
+
+	return cached(ctx, s.cache, dashboardCacheNamespace, "summary", key, dashboardCacheTTL, func() (*dto.DashboardSummary, error) {
+		return s.loadDashboard(ctx, cinemaIDs)
+	})
+}
+
+func (s *ReportService) loadDashboard(ctx context.Context, cinemaIDs []int) (*dto.DashboardSummary, error) {
+	summary := &dto.DashboardSummary{GeneratedAt: time.Now()}
+
+	// Every query binds the cinema scope as $1, NULL meaning no restriction.
+	var scope any
+	if cinemaIDs != nil {
+		scope = cinemaIDs
+	}
+	transactionScope := `($1::int[] IS NULL OR EXISTS (
+		SELECT 1 FROM tickets tk
+		JOIN showtimes s ON s.showtime_id = tk.showtime_id
+		WHERE tk.transaction_id = t.transaction_id AND s.cinema_id = ANY($1)
+	))`
+
+	err := s.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COALESCE(SUM(t.total_amount), 0)::float8, COALESCE(SUM(t.total_seats), 0)::int, COUNT(*)::int
+		FROM transactions t
+		WHERE t.status = 'paid' AND t.paid_at >= CURRENT_DATE AND %s`, transactionScope),
+		scope).Scan(&summary.Today.Revenue, &summary.Today.TicketsSold, &summary.Today.Transactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's sales: %w", err)
+	}
+
+	err = s.db.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE created_at >= CURRENT_DATE)::int,
+		       COUNT(*) FILTER (WHERE created_at >= CURRENT_DATE - 6)::int
+		FROM users`).Scan(&summary.NewRegistrations.Today, &summary.NewRegistrations.Last7Days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registrations: %w", err)
+	}
+
+	for _, order := range []string{"ASC", "DESC"} {
+		rows, err := s.db.Query(ctx, fmt.Sprintf(`
+			SELECT s.showtime_id, m.title, c.name, s.show_datetime, c.total_seats,
+			       GREATEST(c.total_seats - s.available_seats, 0)
+			FROM showtimes s
+			JOIN movies m ON m.movie_id = s.movie_id
+			JOIN cinemas c ON c.id = s.cinema_id
+			WHERE s.show_datetime > NOW() AND s.show_datetime < NOW() + $2 * INTERVAL '1 second'
+			  AND c.total_seats > 0
+			  AND ($1::int[] IS NULL OR s.cinema_id = ANY($1))
+			ORDER BY (c.total_seats - s.available_seats)::float8 / c.total_seats %s, s.show_datetime
+			LIMIT $3`, order),
+			scope, int(dashboardUpcomingWindow.Seconds()), dashboardFillLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get upcoming showtimes: %w", err)
+		}
+
+		showtimes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.ShowtimeFill, error) {
+			var f dto.ShowtimeFill
+			if err := row.Scan(&f.ShowtimeID, &f.MovieTitle, &f.CinemaName, &f.ShowDatetime, &f.TotalSeats, &f.SeatsBooked); err != nil {
+				return f, err
+			}
+			f.FillRate = roundAmount(float64(f.SeatsBooked) * 100 / float64(f.TotalSeats))
+			return f, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect upcoming showtimes: %w", err)
+		}
+
+		if order == "ASC" {
+			summary.LowestFill = showtimes
+		} else {
+			summary.HighestFill = showtimes
+		}
+	}
+
+	err = s.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COUNT(*)::int FROM transactions t
+		WHERE t.status = 'pending' AND t.expires_at > NOW() AND %s`, transactionScope),
+		scope).Scan(&summary.PendingTransactions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending transactions: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`
+		SELECT t.transaction_code, t.recipient_email, t.total_amount::float8, t.expires_at
+		FROM transactions t
+		WHERE t.status = 'pending' AND t.expires_at > NOW() AND t.expires_at < NOW() + $2 * INTERVAL '1 second'
+		  AND %s
+		ORDER BY t.expires_at`, transactionScope),
+		scope, int(dashboardExpiryWindow.Seconds()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expiring transactions: %w", err)
+	}
+	summary.ExpiringTransactions, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.ExpiringTransaction, error) {
+		var t dto.ExpiringTransaction
+		err := row.Scan(&t.TransactionCode, &t.RecipientEmail, &t.TotalAmount, &t.ExpiresAt)
+		return t, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect expiring transactions: %w", err)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GenreService struct {
+	db    *pgxpool.Pool
+	cache *Cache
+}
+
+func NewGenreService(db *pgxpool.Pool, cache *Cache) *GenreService {
+	return &GenreService{db: db, cache: cache}
+}
+
+// GetGenres lists every genre with the number of movies tagged with it.
+func (s *GenreService) GetGenres(ctx context.Context) ([]dto.GenreResponse, error) {
+	return cached(ctx, s.cache, catalogCacheNamespace, "genres", "with-counts", genresCacheTTL, func() ([]dto.GenreResponse, error) {
+		rows, err := s.db.Query(ctx, `
+			SELECT g.id, g.name, COUNT(m.movie_id)
+			FROM genres g
+			LEFT JOIN movies_genres mg ON mg.genre_id = g.id
+			LEFT JOIN movies m ON m.movie_id = mg.movie_id AND m.deleted_at IS NULL
+			GROUP BY g.id, g.name
+			ORDER BY g.name`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get genres: %w", err)
+		}
+
+		genres, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.GenreResponse, error) {
+			var g dto.GenreResponse
+			err := row.Scan(&g.ID, &g.Name, &g.MovieCount)
+			return g, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect genres: %w", err)
+		}
+
+		return genres, nil
+	})
+}
+
+func (s *GenreService) CreateGenre(ctx context.Context, actorID int, name string) (*models.Genre, int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, http.StatusBadRequest, errors.New("genre name is required")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	if status, err := ensureGenreNameFree(ctx, tx, name, 0); err != nil {
+		return nil, status, err
+	}
+
+	genre := models.Genre{Name: name}
+	err = tx.QueryRow(ctx,
+		"INSERT INTO genres (name) VALUES ($1) RETURNING id", name).Scan(&genre.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to create genre: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "genre.created",
+		EntityType: "genre",
+		EntityID:   auditEntityID(genre.ID),
+		Metadata:   map[string]any{"name": name},
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return &genre, http.StatusCreated, nil
+}
+
+func (s *GenreService) RenameGenre(ctx context.Context, actorID, genreID int, name string) (*models.Genre, int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, http.StatusBadRequest, errors.New("genre name is required")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	if status, err := ensureGenreNameFree(ctx, tx, name, genreID); err != nil {
+		return nil, status, err
+	}
+
+	var oldName string
+	err = tx.QueryRow(ctx,
+		"SELECT name FROM genres WHERE id = $1 FOR UPDATE", genreID).Scan(&oldName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("genre not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get genre: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE genres SET name = $2 WHERE id = $1", genreID, name)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to rename genre: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "genre.renamed",
+		EntityType: "genre",
+		EntityID:   auditEntityID(genreID),
+		Metadata:   map[string]any{"from": oldName, "to": name},
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return &models.Genre{ID: genreID, Name: name}, http.StatusOK, nil
+}
+
+// MergeGenre moves every movie tagged with sourceID to targetID and deletes the
+// source genre. Movies already tagged with both keep a single target tag.
+func (s *GenreService) MergeGenre(ctx context.Context, actorID, sourceID, targetID int) (int, error) {
+	if sourceID == targetID {
+		return http.StatusBadRequest, errors.New("cannot merge a genre into itself")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		"SELECT id, name FROM genres WHERE id = ANY($1) ORDER BY id FOR UPDATE", []int{sourceID, targetID})
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get genres: %w", err)
+	}
+	genres, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Genre])
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get genres: %w", err)
+	}
+	if len(genres) != 2 {
+		return http.StatusNotFound, errors.New("genre not found")
+	}
+
+	moved, err := tx.Exec(ctx, `
+		INSERT INTO movies_genres (movie_id, genre_id)
+		SELECT movie_id, $2 FROM movies_genres WHERE genre_id = $1
+		ON CONFLICT (movie_id, genre_id) DO NOTHING`,
+		sourceID, targetID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to reassign movies: %w", err)
+	}
+
+	// Deleting the source genre cascades to its remaining movies_genres rows.
+	_, err = tx.Exec(ctx, "DELETE FROM genres WHERE id = $1", sourceID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete genre: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "genre.merged",
+		EntityType: "genre",
+		EntityID:   auditEntityID(sourceID),
+		Metadata: map[string]any{
+			"target_id":    targetID,
+			"movies_moved": moved.RowsAffected(),
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return http.StatusOK, nil
+}
+
+// DeleteGenre removes a genre that no movie uses. Genres in use have to be
+// merged into another genre instead.
+func (s *GenreService) DeleteGenre(ctx context.Context, actorID, genreID int) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var inUse bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM movies_genres WHERE genre_id = $1)", genreID).Scan(&inUse)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check genre usage: %w", err)
+	}
+	if inUse {
+		return http.StatusConflict, errors.New("genre is used by movies, merge it into another genre instead")
+	}
+
+	var name string
+	err = tx.QueryRow(ctx,
+		"DELETE FROM genres WHERE id = $1 RETURNING name", genreID).Scan(&name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, errors.New("genre not found")
+	} else if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete genre: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "genre.deleted",
+		EntityType: "genre",
+		EntityID:   auditEntityID(genreID),
+		Metadata:   map[string]any{"name": name},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return http.StatusOK, nil
+}
+
+func ensureGenreNameFree(ctx context.Context, tx pgx.Tx, name string, exceptID int) (int, error) {
+	var exists bool
+	err := tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM genres WHERE LOWER(name) = LOWER($1) AND id <> $2)",
+		name, exceptID).Scan(&exists)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check genre name: %w", err)
+	}
+	if exists {
+		return http.StatusConflict, errors.New("genre already exists")
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"noir-backend/utils"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// groupBookingWindow is how long invitees have to pay their share.
+	groupBookingWindow = 24 * time.Hour
+	// groupBookingCutoff releases unpaid groups this long before the show so
+	// the seats can still be sold.
+	groupBookingCutoff = time.Hour
+	// groupBookingMinWindow is the shortest payment window worth inviting
+	// people for, later showtimes are booked with a regular transaction.
+	groupBookingMinWindow = 15 * time.Minute
+)
+
+type groupShare struct {
+	email string
+	seats int
+}
+
+// CreateGroupBooking reserves the seats of a group under one pending
+// transaction and splits the price into shares, one per invitee plus the
+// organiser's own for any seats left over. The group is only paid once every
+// share is, otherwise it is released at expiry like any pending transaction.
+func (s *TransactionService) CreateGroupBooking(ctx context.Context, req dto.CreateGroupBookingRequest, userID int) (*dto.GroupBookingResponse, int, error) {
+	shares, err := splitGroupShares(req)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var showDatetime time.Time
+	var price float64
+	err = tx.QueryRow(ctx,
+		"SELECT show_datetime, price FROM showtimes WHERE showtime_id = $1",
+		req.ShowtimeID).Scan(&showDatetime, &price)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("showtime not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get showtime: %w", err)
+	}
+
+	window := showDatetime.Add(-groupBookingCutoff).Sub(wallClock(time.Now()))
+	if window < groupBookingMinWindow {
+		return nil, http.StatusBadRequest, errors.New("showtime starts too soon for a group booking")
+	}
+	window = min(window, groupBookingWindow)
+
+	transaction, tickets, err := s.reserveSeats(ctx, tx, req.CreateTransactionRequest, userID, time.Now().Add(window))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	codes := make(map[string]string, len(shares))
+	for _, share := range shares {
+		code := utils.GenerateShareCode()
+		_, err = tx.Exec(ctx, `
+			INSERT INTO group_booking_shares (transaction_id, share_code, email, seats, amount)
+			VALUES ($1, $2, $3, $4, $5)`,
+			transaction.TransactionID, code, share.email, share.seats, price*float64(share.seats))
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to create share for %s: %w", share.email, err)
+		}
+		codes[share.email] = code
+	}
+
+	if err := recordPaymentEvent(ctx, tx, transaction.TransactionID, paymentEventCreated, &userID, "group booking"); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &userID,
+		Action:     "transaction.group_created",
+		EntityType: "transaction",
+		EntityID:   auditEntityID(transaction.TransactionID),
+		Metadata: map[string]any{
+			"transaction_code": transaction.TransactionCode,
+			"showtime_id":      req.ShowtimeID,
+			"seats":            req.SeatNumbers,
+			"total_amount":     transaction.TotalAmount,
+			"shares":           len(shares),
+		},
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	organiser := strings.ToLower(req.RecipientEmail)
+	for _, share := range shares {
+		if share.email == organiser {
+			continue
+		}
+		detail, _, err := s.GetGroupShare(ctx, codes[share.email])
+		if err == nil {
+			err = sendGroupInvitation(detail)
+		}
+		if err != nil {
+			log.Printf("Failed to send group booking invitation for %s to %s: %v", transaction.TransactionCode, share.email, err)
+		}
+	}
+
+	response, err := s.groupBookingResponse(ctx, transaction, tickets)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return response, http.StatusCreated, nil
+}
+
+// splitGroupShares validates the invitees of a group booking and appends the
+// organiser's share for the seats nobody else pays for. Emails are
+// lowercased so one person cannot be invited twice.
+func splitGroupShares(req dto.CreateGroupBookingRequest) ([]groupShare, error) {
+	organiser := strings.ToLower(req.RecipientEmail)
+	seen := map[string]bool{}
+	shares := make([]groupShare, 0, len(req.Shares)+1)
+	assigned := 0
+	for _, share := range req.Shares {
+		email := strings.ToLower(strings.TrimSpace(share.Email))
+		if email == organiser {
+			return nil, errors.New("the organiser pays for the seats not assigned to invitees and cannot be invited")
+		}
+		if seen[email] {
+			return nil, fmt.Errorf("%s is invited more than once", email)
+		}
+		seen[email] = true
+		assigned += share.Seats
+		shares = append(shares, groupShare{email: email, seats: share.Seats})
+	}
+
+	if assigned > len(req.SeatNumbers) {
+		return nil, fmt.Errorf("shares cover %d seats but only %d are booked", assigned, len(req.SeatNumbers))
+	}
+	if remaining := len(req.SeatNumbers) - assigned; remaining > 0 {
+		shares = append(shares, groupShare{email: organiser, seats: remaining})
+	}
+	return shares, nil
+}
+
+// GetGroupBooking returns a group booking with the state of every share. Only
+// the organiser can see it.
+func (s *TransactionService) GetGroupBooking(ctx context.Context, transactionCode string, userID int) (*dto.GroupBookingResponse, int, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT transaction_id, transaction_code, recipient_email, recipient_full_name,
+		       recipient_phone_number, total_seats, total_amount, status,
+		       created_at, expires_at, paid_at, created_by, payment_method_id
+		FROM transactions t
+		WHERE transaction_code = $1 AND created_by = $2
+		  AND EXISTS (SELECT 1 FROM group_booking_shares gs WHERE gs.transaction_id = t.transaction_id)`,
+		transactionCode, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get group booking: %w", err)
+	}
+	transaction, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Transaction])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("group booking not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get group booking: %w", err)
+	}
+
+	rows, err = s.db.Query(ctx, `
+		SELECT ticket_id, ticket_code, showtime_id, seat_number, status, transaction_id, created_at
+		FROM tickets
+		WHERE transaction_id = $1
+		ORDER BY seat_number`,
+		transaction.TransactionID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get tickets: %w", err)
+	}
+	tickets, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Ticket])
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to collect tickets: %w", err)
+	}
+
+	response, err := s.groupBookingResponse(ctx, transaction, tickets)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return response, http.StatusOK, nil
+}
+
+func (s *TransactionService) groupBookingResponse(ctx context.Context, transaction models.Transaction, tickets []models.Ticket) (*dto.GroupBookingResponse, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT share_code, email, seats, amount, status, paid_at
+		FROM group_booking_shares
+		WHERE transaction_id = $1
+		ORDER BY id`,
+		transaction.TransactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shares: %w", err)
+	}
+	shares, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.GroupShareResponse, error) {
+		var share dto.GroupShareResponse
+		err := row.Scan(&share.ShareCode, &share.Email, &share.Seats, &share.Amount, &share.Status, &share.PaidAt)
+		return share, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect shares: %w", err)
+	}
+
+	response := &dto.GroupBookingResponse{
+		Transaction: toTransactionResponse(transaction),
+		Tickets:     toTicketResponse(tickets),
+		Shares:      shares,
+	}
+	for _, share := range shares {
+		if share.Status == "paid" {
+			response.PaidAmount += share.Amount
+		}
+	}
+	response.PaidAmount = math.Round(response.PaidAmount*100) / 100
+	if transaction.Status == "pending" {
+		response.OutstandingAmount = math.Round((transaction.TotalAmount-response.PaidAmount)*100) / 100
+	}
+	return response, nil
+}
+
+// GetGroupShare returns what an invitee is asked to pay for. The share code
+// is the invitee's only credential, so anyone holding it may view and pay.
+func (s *TransactionService) GetGroupShare(ctx context.Context, shareCode string) (*dto.GroupShareDetailResponse, int, error) {
+	var detail dto.GroupShareDetailResponse
+	share := &detail.Share
+	err := s.db.QueryRow(ctx, `
+		SELECT gs.share_code, gs.email, gs.seats, gs.amount, gs.status, gs.paid_at,
+		       t.transaction_code, t.recipient_full_name, t.status, t.expires_at,
+		       s.showtime_id, s.show_datetime, s.price,
+		       m.movie_id, m.title,
+		       c.id, c.name, c.location
+		FROM group_booking_shares gs
+		JOIN transactions t ON t.transaction_id = gs.transaction_id
+		JOIN LATERAL (
+			SELECT tk.showtime_id FROM tickets tk WHERE tk.transaction_id = t.transaction_id LIMIT 1
+		) ts ON true
+		JOIN showtimes s ON s.showtime_id = ts.showtime_id
+		JOIN movies m ON m.movie_id = s.movie_id
+		JOIN cinemas c ON c.id = s.cinema_id
+		WHERE gs.share_code = $1`,
+		shareCode).Scan(
+		&share.ShareCode, &share.Email, &share.Seats, &share.Amount, &share.Status, &share.PaidAt,
+		&detail.TransactionCode, &detail.OrganiserName, &detail.GroupStatus, &detail.ExpiresAt,
+		&detail.Showtime.ShowtimeID, &detail.Showtime.ShowDatetime, &detail.Showtime.Price,
+		&detail.Movie.MovieID, &detail.Movie.Title,
+		&detail.Cinema.CinemaID, &detail.Cinema.Name, &detail.Cinema.Location)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("share not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get share: %w", err)
+	}
+
+	return &detail, http.StatusOK, nil
+}
+
+// PayGroupShare records the payment of one share. The payment that settles
+// the last outstanding share marks the whole group paid. A group past its
+// deadline is released instead, as the expiry job would.
+func (s *TransactionService) PayGroupShare(ctx context.Context, userID int, shareCode string, req dto.PayGroupShareRequest) (*dto.GroupShareDetailResponse, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var shareID, transactionID int
+	var email, shareStatus, transactionCode, groupStatus string
+	var amount float64
+	var expiresAt time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT gs.id, gs.email, gs.amount, gs.status,
+		       t.transaction_id, t.transaction_code, t.status, t.expires_at
+		FROM group_booking_shares gs
+		JOIN transactions t ON t.transaction_id = gs.transaction_id
+		WHERE gs.share_code = $1
+		FOR UPDATE`,
+		shareCode).Scan(&shareID, &email, &amount, &shareStatus,
+		&transactionID, &transactionCode, &groupStatus, &expiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("share not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get share: %w", err)
+	}
+
+	if groupStatus != "pending" {
+		return nil, http.StatusConflict, fmt.Errorf("group booking is %s", groupStatus)
+	}
+	if shareStatus != "pending" {
+		return nil, http.StatusConflict, fmt.Errorf("share is already %s", shareStatus)
+	}
+	if err := checkMovieBookable(ctx, tx, transactionID); err != nil {
+		return nil, http.StatusConflict, err
+	}
+	if time.Now().After(expiresAt) {
+		// Release the locks first, cancelTransaction takes them again.
+		tx.Rollback(ctx)
+		_, _, err = s.cancelTransaction(ctx, transactionCode, transactionCancellation{Event: paymentEventExpired})
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("group booking expired and failed to cancel: %w", err)
+		}
+		return nil, http.StatusConflict, errors.New("group booking has expired")
+	}
+
+	var active bool
+	err = tx.QueryRow(ctx,
+		"SELECT is_active FROM payment_method WHERE payment_method_id = $1",
+		req.PaymentMethodID).Scan(&active)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !active) {
+		return nil, http.StatusBadRequest, errors.New("payment method not found or inactive")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get payment method: %w", err)
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx, `
+		UPDATE group_booking_shares
+		SET status = 'paid', paid_at = $2, paid_by = $3, payment_method_id = $4
+		WHERE id = $1`,
+		shareID, now, userID, req.PaymentMethodID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update share: %w", err)
+	}
+	if err := recordPaymentEvent(ctx, tx, transactionID, paymentEventSharePaid, &userID, email); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &userID,
+		Action:     "transaction.share_paid",
+		EntityType: "transaction",
+		EntityID:   auditEntityID(transactionID),
+		Metadata: map[string]any{
+			"transaction_code":  transactionCode,
+			"share_email":       email,
+			"amount":            amount,
+			"payment_method_id": req.PaymentMethodID,
+		},
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	var outstanding int
+	err = tx.QueryRow(ctx,
+		"SELECT COUNT(*) FROM group_booking_shares WHERE transaction_id = $1 AND status = 'pending'",
+		transactionID).Scan(&outstanding)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to count outstanding shares: %w", err)
+	}
+	if outstanding == 0 {
+		_, err = tx.Exec(ctx,
+			"UPDATE transactions SET status = 'paid', paid_at = $2 WHERE transaction_id = $1",
+			transactionID, now)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to update transaction status: %w", err)
+		}
+		if err := recordPaymentEvent(ctx, tx, transactionID, paymentEventPaid, nil, "all shares paid"); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+		err = recordAudit(ctx, tx, models.AuditLog{
+			Action:     "transaction.paid",
+			EntityType: "transaction",
+			EntityID:   auditEntityID(transactionID),
+			Metadata: map[string]any{
+				"transaction_code": transactionCode,
+				"group":            true,
+			},
+			Changes: auditDiff(map[string]any{"status": "pending"}, map[string]any{"status": "paid"}),
+		})
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetGroupShare(ctx, shareCode)
+}
+
+func sendGroupInvitation(detail *dto.GroupShareDetailResponse) error {
+	data := struct {
+		OrganiserName string
+		MovieTitle    string
+		CinemaName    string
+		Showtime      string
+		Seats         int
+		Amount        string
+		ShareCode     string
+		Deadline      string
+		ShareURL      string
+	}{
+		OrganiserName: detail.OrganiserName,
+		MovieTitle:    detail.Movie.Title,
+		CinemaName:    detail.Cinema.Name,
+		Showtime:      detail.Showtime.ShowDatetime.Format("Monday, 2 January 2006 15:04"),
+		Seats:         detail.Share.Seats,
+		Amount:        fmt.Sprintf("%.2f", detail.Share.Amount),
+		ShareCode:     detail.Share.ShareCode,
+		Deadline:      detail.ExpiresAt.Format("Monday, 2 January 2006 15:04"),
+		ShareURL:      fmt.Sprintf("%s/group-bookings/shares/%s", utils.Load().AppURL, detail.Share.ShareCode),
+	}
+
+	body, err := utils.RenderMailTemplate("group_booking_invitation_email.txt", data)
+	if err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("%s invited you to see %s", detail.OrganiserName, detail.Movie.Title)
+	return utils.SendMail(detail.Share.Email, subject, body)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"fmt"
+	"noir-backend/dto"
+	"reflect"
+	"testing"
+)
+
+func TestSplitGroupShares(t *testing.T) {
+	request := func(seats int, shares ...dto.GroupShareRequest) dto.CreateGroupBookingRequest {
+		req := dto.CreateGroupBookingRequest{Shares: shares}
+		req.RecipientEmail = "Organiser@Mail.com"
+		for i := range seats {
+			req.SeatNumbers = append(req.SeatNumbers, fmt.Sprintf("A%d", i+1))
+		}
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		req     dto.CreateGroupBookingRequest
+		want    []groupShare
+		wantErr bool
+	}{
+		{
+			name: "organiser pays for the remaining seats",
+			req: request(4,
+				dto.GroupShareRequest{Email: "Ana@Mail.com", Seats: 1},
+				dto.GroupShareRequest{Email: " budi@mail.com ", Seats: 2}),
+			want: []groupShare{
+				{email: "ana@mail.com", seats: 1},
+				{email: "budi@mail.com", seats: 2},
+				{email: "organiser@mail.com", seats: 1},
+			},
+		},
+		{
+			name: "invitees cover every seat",
+			req:  request(2, dto.GroupShareRequest{Email: "ana@mail.com", Seats: 2}),
+			want: []groupShare{{email: "ana@mail.com", seats: 2}},
+		},
+		{
+			name:    "organiser invited",
+			req:     request(2, dto.GroupShareRequest{Email: "organiser@mail.com", Seats: 1}),
+			wantErr: true,
+		},
+		{
+			name: "invitee listed twice",
+			req: request(3,
+				dto.GroupShareRequest{Email: "ana@mail.com", Seats: 1},
+				dto.GroupShareRequest{Email: "ANA@mail.com", Seats: 1}),
+			wantErr: true,
+		},
+		{
+			name:    "more seats assigned than booked",
+			req:     request(2, dto.GroupShareRequest{Email: "ana@mail.com", Seats: 3}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitGroupShares(tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitGroupShares: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitGroupShares() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
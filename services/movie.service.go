@@ -8,109 +8,134 @@ import (
 	"noir-backend/dto"
 	"noir-backend/models"
 	"noir-backend/utils"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const (
+	catalogCacheNamespace = "catalog"
+	nowPlayingCacheTTL    = 5 * time.Minute
+	upcomingCacheTTL      = 15 * time.Minute
+	movieDetailCacheTTL   = 10 * time.Minute
+	genresCacheTTL        = time.Hour
+)
+
 type MovieService struct {
-	db *pgxpool.Pool
+	db    *pgxpool.Pool
+	cache *Cache
 }
 
-func NewMovieService(db *pgxpool.Pool) *MovieService {
-	return &MovieService{db: db}
+func NewMovieService(db *pgxpool.Pool, cache *Cache) *MovieService {
+	return &MovieService{db: db, cache: cache}
 }
 
-func (s *MovieService) CreateMovie(ctx context.Context, req dto.CreateMovieRequest, posterPath, backdropPath *string) (*dto.MovieResponse, error) {
+// CacheStats reports hit and miss counts of the catalog cache since startup.
+func (s *MovieService) CacheStats() []CacheStats {
+	return s.cache.Stats()
+}
+
+type cachedMoviePage struct {
+	Movies []dto.MovieResponse `json:"movies"`
+	Total  int                 `json:"total"`
+}
+
+func (s *MovieService) CreateMovie(ctx context.Context, actorID int, req dto.CreateMovieRequest, posterPath, backdropPath *string) (*dto.MovieResponse, error) {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("database transaction error")
 	}
 	defer tx.Rollback(ctx)
 
-	directorID, err := getOrCreateDirectorID(ctx, tx, req.Director)
+	movie, castNames, err := insertMovie(ctx, tx, req, posterPath, backdropPath)
 	if err != nil {
 		return nil, err
 	}
 
-	row, err := tx.Query(ctx,
-		`INSERT INTO movies (title, poster_path, backdrop_path, overview, duration, release_date, directors_id, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
-		 RETURNING movie_id, title, poster_path, backdrop_path, overview, duration, release_date, directors_id, created_at, updated_at`,
-		req.Title, posterPath, backdropPath, req.Overview, req.Duration, req.ReleaseDate, directorID)
-
+	genreNames, err := getGenreNames(tx, ctx, req.GenreIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create movie: %w", err)
+		return nil, err
 	}
 
-	movie, err := pgx.CollectOneRow[models.Movie](row, pgx.RowToStructByName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create movie: %w", err)
+	if err := auditMovieChange(ctx, tx, actorID, "movie.created", movie.MovieID, nil); err != nil {
+		return nil, err
 	}
 
-	for _, genre := range req.GenreIDs {
-		_, err = tx.Exec(ctx,
-			"INSERT INTO movies_genres (movie_id, genre_id) VALUES ($1, $2)",
-			movie.MovieID, genre)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add genre: %w", err)
-
-		}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction")
 	}
 
-	for _, actor := range req.Cast {
-		actorID, err := getOrCreateActorID(ctx, tx, actor)
-		if err != nil {
-			return nil, err
-		}
-		_, err = tx.Exec(ctx,
-			"INSERT INTO movies_cast (movie_id, actor_id) VALUES ($1, $2)",
-			movie.MovieID, actorID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add movies_cast: %w", err)
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
 
-		}
+	result := dto.MovieResponse{
+		MovieID:           movie.MovieID,
+		Title:             movie.Title,
+		PosterPath:        movie.PosterPath,
+		BackdropPath:      movie.BackdropPath,
+		Overview:          movie.Overview,
+		Duration:          movie.Duration,
+		ReleaseDate:       movie.ReleaseDate,
+		AgeRating:         movie.AgeRating,
+		ContentAdvisories: movie.ContentAdvisories,
+		Director:          req.Director,
+		Genre:             genreNames,
+		Cast:              castNames,
+		CreatedAt:         movie.CreatedAt,
+		UpdatedAt:         movie.UpdatedAt,
+	}
 
-		firstName, lastName := utils.SplitFullName(actor)
-		_, err = tx.Exec(ctx,
-			"INSERT INTO actors (first_name, last_name) VALUES ($1, $2)",
-			firstName, lastName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to add actor: %w", err)
+	return &result, nil
+}
 
+// insertMovie adds a movie with its director, genres and cast inside tx and
+// returns it with the names of the cast.
+func insertMovie(ctx context.Context, tx pgx.Tx, req dto.CreateMovieRequest, posterPath, backdropPath *string) (*models.Movie, []string, error) {
+	var directorID *int
+	if req.Director != "" {
+		id, err := getOrCreateDirectorID(ctx, tx, req.Director)
+		if err != nil {
+			return nil, nil, err
 		}
+		directorID = &id
+	}
+
+	row, err := tx.Query(ctx,
+		`INSERT INTO movies (title, poster_path, backdrop_path, overview, duration, release_date, director_id, age_rating, content_advisories, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		 RETURNING movie_id, title, poster_path, backdrop_path, overview, duration, release_date, director_id, age_rating, content_advisories, created_at, updated_at`,
+		req.Title, posterPath, backdropPath, req.Overview, req.Duration, req.ReleaseDate, directorID, req.AgeRating, req.ContentAdvisories)
 
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create movie: %w", err)
 	}
 
-	genreNames, err := getGenreNames(tx, ctx, req.GenreIDs)
+	movie, err := pgx.CollectOneRow[models.Movie](row, pgx.RowToStructByName)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("failed to create movie: %w", err)
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction")
+	for _, genre := range req.GenreIDs {
+		_, err = tx.Exec(ctx,
+			"INSERT INTO movies_genres (movie_id, genre_id) VALUES ($1, $2)",
+			movie.MovieID, genre)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to add genre: %w", err)
+
+		}
 	}
 
-	result := dto.MovieResponse{
-		MovieID:      movie.MovieID,
-		Title:        movie.Title,
-		PosterPath:   movie.PosterPath,
-		BackdropPath: movie.BackdropPath,
-		Overview:     movie.Overview,
-		Duration:     movie.Duration,
-		ReleaseDate:  movie.ReleaseDate,
-		Director:     req.Director,
-		Genre:        genreNames,
-		Cast:         req.Cast,
-		CreatedAt:    movie.CreatedAt,
-		UpdatedAt:    movie.UpdatedAt,
+	castNames, err := insertCastEntries(ctx, tx, movie.MovieID, req.Cast)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return &result, nil
+	return &movie, castNames, nil
 }
 
-func (s *MovieService) UpdateMovie(ctx context.Context, id int, req dto.UpdateMovieRequest, backdropPath, posterPath *string) (int, error) {
+func (s *MovieService) UpdateMovie(ctx context.Context, actorID, id int, req dto.UpdateMovieRequest, backdropPath, posterPath *string) (int, error) {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return http.StatusInternalServerError, fmt.Errorf("database transaction error: %v", err)
@@ -119,12 +144,17 @@ func (s *MovieService) UpdateMovie(ctx context.Context, id int, req dto.UpdateMo
 
 	var exists bool
 	err = tx.QueryRow(ctx,
-		"SELECT EXISTS(SELECT 1 FROM movies WHERE movie_id = $1)", id).Scan(&exists)
+		"SELECT EXISTS(SELECT 1 FROM movies WHERE movie_id = $1 AND deleted_at IS NULL)", id).Scan(&exists)
 
 	if err != nil || !exists {
 		return http.StatusNotFound, fmt.Errorf("movie not found")
 	}
 
+	before, err := movieAuditState(ctx, tx, id)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
 	setParts := []string{"updated_at = NOW()"}
 	args := []interface{}{}
 	argIndex := 1
@@ -149,9 +179,23 @@ func (s *MovieService) UpdateMovie(ctx context.Context, id int, req dto.UpdateMo
 		args = append(args, *req.ReleaseDate)
 		argIndex++
 	}
+	if req.AgeRating != nil {
+		setParts = append(setParts, fmt.Sprintf("age_rating = $%d", argIndex))
+		args = append(args, *req.AgeRating)
+		argIndex++
+	}
+	if req.ContentAdvisories != nil {
+		setParts = append(setParts, fmt.Sprintf("content_advisories = $%d", argIndex))
+		args = append(args, *req.ContentAdvisories)
+		argIndex++
+	}
 	if req.Director != nil {
-		setParts = append(setParts, fmt.Sprintf("director = $%d", argIndex))
-		args = append(args, *req.Director)
+		directorID, err := getOrCreateDirectorID(ctx, tx, *req.Director)
+		if err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("failed to update director: %v", err)
+		}
+		setParts = append(setParts, fmt.Sprintf("director_id = $%d", argIndex))
+		args = append(args, directorID)
 		argIndex++
 	}
 	if posterPath != nil {
@@ -191,7 +235,7 @@ func (s *MovieService) UpdateMovie(ctx context.Context, id int, req dto.UpdateMo
 
 		for _, genre := range *req.GenreIDs {
 			_, err = tx.Exec(ctx,
-				"INSERT INTO movies_genres (movie_id, genre) VALUES ($1, $2)",
+				"INSERT INTO movies_genres (movie_id, genre_id) VALUES ($1, $2)",
 				id, genre)
 			if err != nil {
 				return http.StatusInternalServerError, fmt.Errorf("failed to add genre")
@@ -203,89 +247,260 @@ func (s *MovieService) UpdateMovie(ctx context.Context, id int, req dto.UpdateMo
 		_, err = tx.Exec(ctx,
 			"DELETE FROM movies_cast WHERE movie_id = $1", id)
 		if err != nil {
-			return http.StatusInternalServerError, fmt.Errorf("failed to update genre")
+			return http.StatusInternalServerError, fmt.Errorf("failed to update cast")
 		}
 
-		for _, cast := range *req.Cast {
-			_, err = tx.Exec(ctx,
-				"INSERT INTO movies_cast (movie_id, cast) VALUES ($1, $2)",
-				id, cast)
-			if err != nil {
-				return http.StatusInternalServerError, fmt.Errorf("failed to add genre")
-			}
+		if _, err = insertCastEntries(ctx, tx, id, *req.Cast); err != nil {
+			return http.StatusInternalServerError, err
 		}
 	}
 
+	if err := auditMovieChange(ctx, tx, actorID, "movie.updated", id, before); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
 	if err = tx.Commit(ctx); err != nil {
 		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
 	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
 
 	return http.StatusOK, nil
 }
 
-func (s *MovieService) DeleteMovie(ctx context.Context, id int) (int, error) {
+// DeleteMovie hides a movie from the catalog while keeping its showtimes and
+// tickets for sales history. Movies with paid tickets for upcoming showtimes
+// cannot be deleted.
+func (s *MovieService) DeleteMovie(ctx context.Context, actorID, id int) (int, error) {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
 	}
 	defer tx.Rollback(ctx)
 
+	var movieID int
+	err = tx.QueryRow(ctx,
+		"SELECT movie_id FROM movies WHERE movie_id = $1 AND deleted_at IS NULL FOR UPDATE", id).Scan(&movieID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, fmt.Errorf("movie not found")
+	} else if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get movie")
+	}
+
+	var hasPaidShowtimes bool
+	err = tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM showtimes s
+			JOIN tickets tk ON tk.showtime_id = s.showtime_id
+			JOIN transactions t ON t.transaction_id = tk.transaction_id
+			WHERE s.movie_id = $1
+			  AND s.show_datetime > NOW()
+			  AND t.status = 'paid'
+			  AND tk.status != 'cancelled'
+		)`, id).Scan(&hasPaidShowtimes)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to check showtimes")
+	}
+	if hasPaidShowtimes {
+		return http.StatusConflict, fmt.Errorf("movie has paid tickets for upcoming showtimes")
+	}
+
+	before, err := movieAuditState(ctx, tx, id)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
 	_, err = tx.Exec(ctx,
-		"DELETE FROM movies_genres WHERE movie_id = $1", id)
+		"UPDATE movies SET deleted_at = NOW(), updated_at = NOW() WHERE movie_id = $1", id)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete movie")
+	}
+
+	if err := auditMovieChange(ctx, tx, actorID, "movie.deleted", id, before); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return http.StatusOK, nil
+}
+
+func (s *MovieService) RestoreMovie(ctx context.Context, actorID, id int) (int, error) {
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("failed to delete movie genre")
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	before, err := movieAuditState(ctx, tx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, fmt.Errorf("deleted movie not found")
+	} else if err != nil {
+		return http.StatusInternalServerError, err
 	}
 
 	result, err := tx.Exec(ctx,
-		"DELETE FROM movies WHERE movie_id = $1", id)
+		"UPDATE movies SET deleted_at = NULL, updated_at = NOW() WHERE movie_id = $1 AND deleted_at IS NOT NULL", id)
 	if err != nil {
-		return http.StatusInternalServerError, fmt.Errorf("failed to delete movie")
+		return http.StatusInternalServerError, fmt.Errorf("failed to restore movie")
+	}
+	if result.RowsAffected() == 0 {
+		return http.StatusNotFound, fmt.Errorf("deleted movie not found")
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return http.StatusNotFound, fmt.Errorf("movie not found")
+	if err := auditMovieChange(ctx, tx, actorID, "movie.restored", id, before); err != nil {
+		return http.StatusInternalServerError, err
 	}
 
 	if err = tx.Commit(ctx); err != nil {
 		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
 	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
 
 	return http.StatusOK, nil
 }
 
+// movieSortOrders whitelists the ORDER BY clauses accepted by
+// getMoviesByCondition. Keys are the values of the sort query parameter.
+var movieSortOrders = map[string]string{
+	"newest":        "m.created_at DESC",
+	"oldest":        "m.created_at ASC",
+	"release_date":  "m.release_date ASC",
+	"-release_date": "m.release_date DESC",
+	"title":         "m.title ASC",
+	"-title":        "m.title DESC",
+	"duration":      "m.duration ASC",
+	"-duration":     "m.duration DESC",
+	// relevance ranks against the search text, which must be bound as $1.
+	"relevance": "ts_rank(m.search_vector, websearch_to_tsquery('english', $1)) DESC, m.release_date DESC",
+}
+
+func IsValidMovieSort(sort string) bool {
+	_, ok := movieSortOrders[sort]
+	return ok
+}
+
 func (s *MovieService) GetUpcomingMovies(ctx context.Context, limit, offset int) ([]dto.MovieResponse, int, error) {
 	now := time.Now().Format("2006-01-02")
-	return s.getMoviesByCondition(ctx, "WHERE m.release_date > $1", []any{now}, limit, offset, "m.release_date ASC")
+	key := fmt.Sprintf("%s:%d:%d", now, limit, offset)
+	page, err := cached(ctx, s.cache, catalogCacheNamespace, "upcoming", key, upcomingCacheTTL, func() (cachedMoviePage, error) {
+		movies, total, err := s.getMoviesByCondition(ctx, "m.release_date > $1", []any{now}, limit, offset, "release_date")
+		return cachedMoviePage{Movies: movies, Total: total}, err
+	})
+	return page.Movies, page.Total, err
 }
 
 func (s *MovieService) GetNowPlayingMovies(ctx context.Context, limit, offset int) ([]dto.MovieResponse, int, error) {
 	now := time.Now().Format("2006-01-02")
-	return s.getMoviesByCondition(ctx, "WHERE m.release_date <= $1", []any{now}, limit, offset, "m.release_date DESC")
+	key := fmt.Sprintf("%s:%d:%d", now, limit, offset)
+	page, err := cached(ctx, s.cache, catalogCacheNamespace, "now_playing", key, nowPlayingCacheTTL, func() (cachedMoviePage, error) {
+		movies, total, err := s.getMoviesByCondition(ctx, "m.release_date <= $1", []any{now}, limit, offset, "-release_date")
+		return cachedMoviePage{Movies: movies, Total: total}, err
+	})
+	return page.Movies, page.Total, err
 }
 
-func (s *MovieService) GetMovies(ctx context.Context, limit, offset int) ([]dto.MovieResponse, int, error) {
-	return s.getMoviesByCondition(ctx, "", nil, limit, offset, "m.created_at DESC")
+func (s *MovieService) GetMovies(ctx context.Context, filter dto.MovieFilter, limit, offset int) ([]dto.MovieResponse, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	// The search text goes first so the relevance order can refer to it as $1.
+	if filter.Query != "" {
+		args = append(args, filter.Query)
+		conditions = append(conditions, "m.search_vector @@ websearch_to_tsquery('english', $1)")
+	}
+	if filter.Genre != "" {
+		if genreID, err := strconv.Atoi(filter.Genre); err == nil {
+			args = append(args, genreID)
+			conditions = append(conditions, fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM movies_genres fmg WHERE fmg.movie_id = m.movie_id AND fmg.genre_id = $%d)", len(args)))
+		} else {
+			args = append(args, filter.Genre)
+			conditions = append(conditions, fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM movies_genres fmg
+				JOIN genres fg ON fg.id = fmg.genre_id
+				WHERE fmg.movie_id = m.movie_id AND fg.name ILIKE $%d)`, len(args)))
+		}
+	}
+	if filter.Director != "" {
+		args = append(args, "%"+filter.Director+"%")
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM directors fd
+			WHERE fd.id = m.director_id AND fd.first_name || ' ' || fd.last_name ILIKE $%d)`, len(args)))
+	}
+	if filter.Actor != "" {
+		args = append(args, "%"+filter.Actor+"%")
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM movies_cast fmc
+			JOIN actors fa ON fa.id = fmc.actor_id
+			WHERE fmc.movie_id = m.movie_id AND fa.first_name || ' ' || fa.last_name ILIKE $%d)`, len(args)))
+	}
+	if filter.ReleaseYear != nil {
+		args = append(args, *filter.ReleaseYear)
+		conditions = append(conditions, fmt.Sprintf("EXTRACT(YEAR FROM m.release_date) = $%d", len(args)))
+	}
+	if filter.MinDuration != nil {
+		args = append(args, *filter.MinDuration)
+		conditions = append(conditions, fmt.Sprintf("m.duration >= $%d", len(args)))
+	}
+	if filter.MaxDuration != nil {
+		args = append(args, *filter.MaxDuration)
+		conditions = append(conditions, fmt.Sprintf("m.duration <= $%d", len(args)))
+	}
+	if len(filter.AgeRatings) > 0 {
+		args = append(args, filter.AgeRatings)
+		conditions = append(conditions, fmt.Sprintf("m.age_rating = ANY($%d)", len(args)))
+	}
+	if len(filter.ExcludeAdvisories) > 0 {
+		args = append(args, filter.ExcludeAdvisories)
+		conditions = append(conditions, fmt.Sprintf("NOT (m.content_advisories && $%d::text[])", len(args)))
+	}
+
+	sort := filter.Sort
+	if sort == "" {
+		sort = "newest"
+		if filter.Query != "" {
+			sort = "relevance"
+		}
+	}
+	if sort == "relevance" && filter.Query == "" {
+		return nil, 0, fmt.Errorf("sort by relevance requires a search query")
+	}
+
+	condition := strings.Join(conditions, " AND ")
+
+	return s.getMoviesByCondition(ctx, condition, args, limit, offset, sort)
 }
 
 func (s *MovieService) GetMovieByID(ctx context.Context, movieID int) (*dto.MovieResponse, error) {
-	movies, _, err := s.getMoviesByCondition(
-		ctx,
-		"WHERE m.movie_id = $1",
-		[]any{movieID},
-		1, 0,
-		"m.created_at DESC",
-	)
+	return cached(ctx, s.cache, catalogCacheNamespace, "movie", strconv.Itoa(movieID), movieDetailCacheTTL, func() (*dto.MovieResponse, error) {
+		movies, _, err := s.getMoviesByCondition(
+			ctx,
+			"m.movie_id = $1",
+			[]any{movieID},
+			1, 0,
+			"newest",
+		)
 
-	if err != nil {
-		return nil, err
-	}
+		if err != nil {
+			return nil, err
+		}
 
-	if len(movies) == 0 {
-		return nil, fmt.Errorf("movie not found")
-	}
+		if len(movies) == 0 {
+			return nil, fmt.Errorf("movie not found")
+		}
+
+		movie := movies[0]
+		movie.CastMembers, err = s.getCastMembers(ctx, movieID)
+		if err != nil {
+			return nil, err
+		}
 
-	return &movies[0], nil
+		return &movie, nil
+	})
 }
 
 func (s *MovieService) getMoviesByCondition(
@@ -293,8 +508,19 @@ func (s *MovieService) getMoviesByCondition(
 	condition string,
 	args []any,
 	limit, offset int,
-	orderBy string,
+	sort string,
 ) ([]dto.MovieResponse, int, error) {
+	orderBy, ok := movieSortOrders[sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid sort: %s", sort)
+	}
+
+	// Soft deleted movies never show up in the catalog.
+	where := "WHERE m.deleted_at IS NULL"
+	if condition != "" {
+		where += " AND (" + condition + ")"
+	}
+
 	query := fmt.Sprintf(`
 		SELECT
 			m.movie_id,
@@ -304,25 +530,37 @@ func (s *MovieService) getMoviesByCondition(
 			m.overview,
 			m.duration,
 			m.release_date,
+			m.age_rating,
+			m.content_advisories,
 			m.created_at,
 			m.updated_at,
 			d.first_name || ' ' || d.last_name AS director,
-			ARRAY_AGG(DISTINCT g.name) AS genres,
-			ARRAY_AGG(DISTINCT a.first_name || ' ' || a.last_name) AS cast
+			COALESCE(ARRAY_AGG(DISTINCT g.name) FILTER (WHERE g.name IS NOT NULL), '{}') AS genres,
+			COALESCE((
+				SELECT ARRAY_AGG(a.first_name || ' ' || a.last_name ORDER BY mc.billing_order NULLS LAST, mc.id)
+				FROM movies_cast mc
+				JOIN actors a ON a.id = mc.actor_id
+				WHERE mc.movie_id = m.movie_id
+			), '{}') AS cast,
+			r.rating,
+			COALESCE(r.rating_count, 0) AS rating_count
 		FROM movies m
+		LEFT JOIN LATERAL (
+			SELECT ROUND(AVG(rv.rating), 1)::float8 AS rating, COUNT(*)::int AS rating_count
+			FROM reviews rv
+			WHERE rv.movie_id = m.movie_id AND rv.status = 'published'
+		) r ON true
 		LEFT JOIN directors d ON d.id = m.director_id
-		LEFT JOIN movie_genres mg ON mg.movie_id = m.movie_id
+		LEFT JOIN movies_genres mg ON mg.movie_id = m.movie_id
 		LEFT JOIN genres g ON g.id = mg.genre_id
-		LEFT JOIN movie_casts mc ON mc.movie_id = m.movie_id
-		LEFT JOIN actors a ON a.id = mc.actor_id
 		%s
 		GROUP BY
 			m.movie_id, m.title, m.poster_path, m.backdrop_path, m.overview,
-			m.duration, m.release_date, m.created_at, m.updated_at,
-			d.first_name, d.last_name
+			m.duration, m.release_date, m.age_rating, m.content_advisories, m.created_at, m.updated_at,
+			d.first_name, d.last_name, r.rating, r.rating_count
 		ORDER BY %s
 		LIMIT $%d OFFSET $%d`,
-		condition, orderBy, len(args)+1, len(args)+2)
+		where, orderBy, len(args)+1, len(args)+2)
 
 	args = append(args, limit, offset)
 
@@ -336,28 +574,36 @@ func (s *MovieService) getMoviesByCondition(
 		return nil, 0, err
 	}
 
-	var movies []dto.MovieResponse
+	movies := []dto.MovieResponse{}
 	for _, row := range flatRows {
-		movies = append(movies, dto.MovieResponse{
-			MovieID:      row.MovieID,
-			Title:        row.Title,
-			PosterPath:   row.PosterPath,
-			BackdropPath: row.BackdropPath,
-			Overview:     row.Overview,
-			Duration:     row.Duration,
-			ReleaseDate:  row.ReleaseDate,
-			Director:     *row.Director,
-			Genre:        *row.Genres,
-			Cast:         *row.Cast,
-			CreatedAt:    row.CreatedAt,
-			UpdatedAt:    row.UpdatedAt,
-		})
-	}
-
-	countQuery := "SELECT COUNT(*) FROM movies"
-	if condition != "" {
-		countQuery += " " + condition
+		movie := dto.MovieResponse{
+			MovieID:           row.MovieID,
+			Title:             row.Title,
+			PosterPath:        row.PosterPath,
+			BackdropPath:      row.BackdropPath,
+			Overview:          row.Overview,
+			Duration:          row.Duration,
+			ReleaseDate:       row.ReleaseDate,
+			AgeRating:         row.AgeRating,
+			ContentAdvisories: row.ContentAdvisories,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+			Rating:            row.Rating,
+			RatingCount:       row.RatingCount,
+		}
+		if row.Director != nil {
+			movie.Director = *row.Director
+		}
+		if row.Genres != nil {
+			movie.Genre = *row.Genres
+		}
+		if row.Cast != nil {
+			movie.Cast = *row.Cast
+		}
+		movies = append(movies, movie)
 	}
+
+	countQuery := "SELECT COUNT(*) FROM movies m " + where
 	var total int
 	err = s.db.QueryRow(ctx, countQuery, args[:len(args)-2]...).Scan(&total)
 	if err != nil {
@@ -367,20 +613,6 @@ func (s *MovieService) getMoviesByCondition(
 	return movies, total, nil
 }
 
-func (s *MovieService) GetGenres(ctx context.Context) (*[]models.Genre, error) {
-	rows, err := s.db.Query(ctx, "SELECT id, name FROM genres")
-	if err != nil {
-		return nil, err
-	}
-
-	genres, err := pgx.CollectRows[models.Genre](rows, pgx.RowToStructByName)
-	if err != nil {
-		return nil, err
-	}
-
-	return &genres, nil
-}
-
 func (s *MovieService) ParseCreateMovieRequest(form map[string][]string) (*dto.CreateMovieRequest, error) {
 	var req dto.CreateMovieRequest
 
@@ -412,6 +644,22 @@ func (s *MovieService) ParseCreateMovieRequest(form map[string][]string) (*dto.C
 		req.Cast = append(req.Cast, cast...)
 	}
 
+	req.AgeRating = utils.AgeRatingSU
+	if rating := utils.GetStringField(form, "age_rating"); rating != nil {
+		req.AgeRating = *rating
+	}
+	if !utils.IsValidAgeRating(req.AgeRating) {
+		return nil, fmt.Errorf("invalid age rating: %s", req.AgeRating)
+	}
+
+	req.ContentAdvisories = []string{}
+	if advisories := utils.GetStringArray(form, "content_advisories"); advisories != nil {
+		req.ContentAdvisories = *advisories
+	}
+	if err := validateContentAdvisories(req.ContentAdvisories); err != nil {
+		return nil, err
+	}
+
 	return &req, nil
 }
 
@@ -447,9 +695,180 @@ func (s *MovieService) ParseUpdateMovieRequest(form map[string][]string) (*dto.U
 		req.Cast = &cast
 	}
 
+	req.AgeRating = utils.GetStringField(form, "age_rating")
+	if req.AgeRating != nil && !utils.IsValidAgeRating(*req.AgeRating) {
+		return nil, fmt.Errorf("invalid age rating: %s", *req.AgeRating)
+	}
+
+	req.ContentAdvisories = utils.GetStringArray(form, "content_advisories")
+	if req.ContentAdvisories != nil {
+		if err := validateContentAdvisories(*req.ContentAdvisories); err != nil {
+			return nil, err
+		}
+	}
+
 	return &req, nil
 }
 
+func validateContentAdvisories(advisories []string) error {
+	for _, advisory := range advisories {
+		if !utils.IsValidContentAdvisory(advisory) {
+			return fmt.Errorf("invalid content advisory: %s", advisory)
+		}
+	}
+	return nil
+}
+
+// ReplaceCast sets the cast of a movie to the given people, creating actor
+// records for people who have not acted before.
+func (s *MovieService) ReplaceCast(ctx context.Context, actorID, movieID int, entries []dto.CastEntryRequest) ([]dto.CastMember, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM movies WHERE movie_id = $1 AND deleted_at IS NULL)", movieID).Scan(&exists)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to find movie: %w", err)
+	}
+	if !exists {
+		return nil, http.StatusNotFound, fmt.Errorf("movie not found")
+	}
+
+	before, err := movieAuditState(ctx, tx, movieID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM movies_cast WHERE movie_id = $1", movieID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update cast")
+	}
+
+	for i, entry := range entries {
+		actorID, err := getOrCreateActorIDForPerson(ctx, tx, entry.PersonID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, http.StatusBadRequest, fmt.Errorf("person %d not found", entry.PersonID)
+		} else if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to find actor: %w", err)
+		}
+
+		billingOrder := entry.BillingOrder
+		if billingOrder == nil {
+			order := i + 1
+			billingOrder = &order
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO movies_cast (movie_id, actor_id, character_name, billing_order) VALUES ($1, $2, $3, $4)",
+			movieID, actorID, entry.CharacterName, billingOrder)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to add cast: %w", err)
+		}
+	}
+
+	if err := auditMovieChange(ctx, tx, actorID, "movie.cast_replaced", movieID, before); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	cast, err := s.getCastMembers(ctx, movieID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return cast, http.StatusOK, nil
+}
+
+func (s *MovieService) getCastMembers(ctx context.Context, movieID int) ([]dto.CastMember, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT a.person_id, a.first_name || ' ' || a.last_name, mc.character_name, mc.billing_order
+		FROM movies_cast mc
+		JOIN actors a ON a.id = mc.actor_id
+		WHERE mc.movie_id = $1
+		ORDER BY mc.billing_order NULLS LAST, mc.id`,
+		movieID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cast: %w", err)
+	}
+
+	cast, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.CastMember, error) {
+		var member dto.CastMember
+		err := row.Scan(&member.PersonID, &member.Name, &member.CharacterName, &member.BillingOrder)
+		return member, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect cast: %w", err)
+	}
+
+	return cast, nil
+}
+
+// insertCastEntries adds the cast of a movie in billing order. Entries are
+// actor names, optionally followed by the character as "Name:Character".
+func insertCastEntries(ctx context.Context, tx pgx.Tx, movieID int, entries []string) ([]string, error) {
+	names := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		name, character := parseCastEntry(entry)
+		if name == "" {
+			continue
+		}
+
+		actorID, err := getOrCreateActorID(ctx, tx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add actor: %w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO movies_cast (movie_id, actor_id, character_name, billing_order) VALUES ($1, $2, $3, $4)",
+			movieID, actorID, character, i+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add movies_cast: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func parseCastEntry(entry string) (string, *string) {
+	name, character, found := strings.Cut(entry, ":")
+	name = strings.Join(strings.Fields(name), " ")
+	character = strings.TrimSpace(character)
+	if !found || character == "" {
+		return name, nil
+	}
+	return name, &character
+}
+
+func getOrCreatePersonID(ctx context.Context, db pgx.Tx, firstName, lastName string) (int, error) {
+	var personID int
+
+	err := db.QueryRow(ctx,
+		"SELECT id FROM people WHERE first_name = $1 AND last_name = $2 ORDER BY id LIMIT 1",
+		firstName, lastName).Scan(&personID)
+	if err == nil {
+		return personID, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	err = db.QueryRow(ctx,
+		"INSERT INTO people (first_name, last_name) VALUES ($1, $2) RETURNING id",
+		firstName, lastName).Scan(&personID)
+	if err != nil {
+		return 0, err
+	}
+
+	return personID, nil
+}
+
 func getOrCreateActorID(ctx context.Context, db pgx.Tx, fullName string) (int, error) {
 	firstName, lastName := utils.SplitFullName(fullName)
 	var actorID int
@@ -466,12 +885,26 @@ func getOrCreateActorID(ctx context.Context, db pgx.Tx, fullName string) (int, e
 		return 0, err
 	}
 
+	personID, err := getOrCreatePersonID(ctx, db, firstName, lastName)
+	if err != nil {
+		return 0, err
+	}
+
+	return getOrCreateActorIDForPerson(ctx, db, personID)
+}
+
+// getOrCreateActorIDForPerson returns pgx.ErrNoRows when the person does not
+// exist.
+func getOrCreateActorIDForPerson(ctx context.Context, db pgx.Tx, personID int) (int, error) {
+	var actorID int
+
 	insertQuery := `
-		INSERT INTO actor (first_name, last_name)
-		VALUES ($1, $2)
+		INSERT INTO actors (first_name, last_name, person_id)
+		SELECT first_name, last_name, id FROM people WHERE id = $1
+		ON CONFLICT (person_id) DO UPDATE SET person_id = EXCLUDED.person_id
 		RETURNING id
 	`
-	err = db.QueryRow(ctx, insertQuery, firstName, lastName).Scan(&actorID)
+	err := db.QueryRow(ctx, insertQuery, personID).Scan(&actorID)
 	if err != nil {
 		return 0, err
 	}
@@ -495,12 +928,18 @@ func getOrCreateDirectorID(ctx context.Context, db pgx.Tx, fullName string) (int
 		return 0, err
 	}
 
+	personID, err := getOrCreatePersonID(ctx, db, firstName, lastName)
+	if err != nil {
+		return 0, err
+	}
+
 	insertQuery := `
-		INSERT INTO directors (first_name, last_name)
-		VALUES ($1, $2)
+		INSERT INTO directors (first_name, last_name, person_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (person_id) DO UPDATE SET person_id = EXCLUDED.person_id
 		RETURNING id
 	`
-	err = db.QueryRow(ctx, insertQuery, firstName, lastName).Scan(&directorID)
+	err = db.QueryRow(ctx, insertQuery, firstName, lastName, personID).Scan(&directorID)
 	if err != nil {
 		return 0, err
 	}
@@ -526,3 +965,72 @@ func getGenreNames(tx pgx.Tx, ctx context.Context, genreIDs []int) ([]string, er
 
 	return genreNames, nil
 }
+
+// movieAuditState captures the editable fields of a movie for the audit log.
+func movieAuditState(ctx context.Context, tx pgx.Tx, movieID int) (map[string]any, error) {
+	var (
+		title, overview, releaseDate, director, ageRating string
+		duration                                          int
+		contentAdvisories, cast                           []string
+		posterPath, backdropPath                          *string
+		genreIDs                                          []int
+		deleted                                           bool
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT m.title, COALESCE(m.overview, ''), m.duration, TO_CHAR(m.release_date, 'YYYY-MM-DD'),
+		       COALESCE(TRIM(d.first_name || ' ' || d.last_name), ''),
+		       m.age_rating, m.content_advisories, m.poster_path, m.backdrop_path,
+		       COALESCE((
+		           SELECT ARRAY_AGG(mg.genre_id ORDER BY mg.genre_id)
+		           FROM movies_genres mg WHERE mg.movie_id = m.movie_id
+		       ), '{}'),
+		       COALESCE((
+		           SELECT ARRAY_AGG(TRIM(a.first_name || ' ' || a.last_name) || COALESCE(':' || mc.character_name, '')
+		               ORDER BY mc.billing_order NULLS LAST, mc.id)
+		           FROM movies_cast mc
+		           JOIN actors a ON a.id = mc.actor_id
+		           WHERE mc.movie_id = m.movie_id
+		       ), '{}'),
+		       m.deleted_at IS NOT NULL
+		FROM movies m
+		LEFT JOIN directors d ON d.id = m.director_id
+		WHERE m.movie_id = $1`,
+		movieID).Scan(&title, &overview, &duration, &releaseDate, &director,
+		&ageRating, &contentAdvisories, &posterPath, &backdropPath, &genreIDs, &cast, &deleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read movie for audit: %w", err)
+	}
+
+	return map[string]any{
+		"title":              title,
+		"overview":           overview,
+		"duration":           duration,
+		"release_date":       releaseDate,
+		"director":           director,
+		"age_rating":         ageRating,
+		"content_advisories": contentAdvisories,
+		"poster_path":        posterPath,
+		"backdrop_path":      backdropPath,
+		"genre_ids":          genreIDs,
+		"cast":               cast,
+		"deleted":            deleted,
+	}, nil
+}
+
+// auditMovieChange records action with the difference between before and the
+// current state of the movie. A nil before records a creation.
+func auditMovieChange(ctx context.Context, tx pgx.Tx, actorID int, action string, movieID int, before map[string]any) error {
+	after, err := movieAuditState(ctx, tx, movieID)
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     action,
+		EntityType: "movie",
+		EntityID:   auditEntityID(movieID),
+		Metadata:   map[string]any{"title": after["title"]},
+		Changes:    auditDiff(before, after),
+	})
+}
@@ -0,0 +1,495 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"noir-backend/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	MovieTransferCSV  = "csv"
+	MovieTransferJSON = "json"
+
+	// MaxMovieImportBytes caps the size of an uploaded import file.
+	MaxMovieImportBytes = 10 << 20
+
+	maxMovieImportRows   = 1000
+	movieTransferListSep = "|"
+)
+
+// csvFormulaPrefixes are the leading characters spreadsheet applications read
+// as the start of a formula.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+var errTooManyImportRows = fmt.Errorf("import is limited to %d movies", maxMovieImportRows)
+
+var movieTransferColumns = []string{
+	"title", "overview", "duration", "release_date", "director", "genres", "cast",
+	"age_rating", "content_advisories", "poster_path", "backdrop_path",
+}
+
+func IsValidMovieTransferFormat(format string) bool {
+	return format == MovieTransferCSV || format == MovieTransferJSON
+}
+
+type movieImportRow struct {
+	row    int
+	movie  dto.MovieTransferRow
+	errors []string
+}
+
+// ImportMovies validates every row of a CSV or JSON import and, unless dryRun
+// is set, adds all of them in one transaction. Nothing is imported when any row
+// is invalid; the report lists the problems per row.
+func (s *MovieService) ImportMovies(ctx context.Context, actorID int, r io.Reader, format string, dryRun bool) (*dto.MovieImportReport, int, error) {
+	var rows []movieImportRow
+	var err error
+	switch format {
+	case MovieTransferCSV:
+		rows, err = parseMovieImportCSV(r)
+	case MovieTransferJSON:
+		rows, err = parseMovieImportJSON(r)
+	default:
+		return nil, http.StatusBadRequest, fmt.Errorf("unsupported format %q, use csv or json", format)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("import file is limited to %d MB", MaxMovieImportBytes>>20)
+	} else if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if len(rows) == 0 {
+		return nil, http.StatusBadRequest, errors.New("import file has no movies")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	requests, err := s.validateMovieImport(ctx, tx, rows)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	report := &dto.MovieImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: []dto.MovieImportRowError{},
+	}
+	for _, row := range rows {
+		if len(row.errors) > 0 {
+			report.Errors = append(report.Errors, dto.MovieImportRowError{
+				Row:    row.row,
+				Title:  row.movie.Title,
+				Errors: row.errors,
+			})
+		}
+	}
+	report.Valid = report.Total - len(report.Errors)
+
+	if dryRun {
+		return report, http.StatusOK, nil
+	}
+	if len(report.Errors) > 0 {
+		return report, http.StatusUnprocessableEntity, errors.New("import has invalid rows, nothing was imported")
+	}
+
+	for i, req := range requests {
+		movie := rows[i].movie
+		if _, _, err := insertMovie(ctx, tx, req, movie.PosterPath, movie.BackdropPath); err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("row %d: %w", rows[i].row, err)
+		}
+		report.Imported++
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "movie.imported",
+		EntityType: "movie",
+		Metadata:   map[string]any{"format": format, "count": report.Imported},
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return report, http.StatusCreated, nil
+}
+
+// validateMovieImport records the problems of each row in row.errors and
+// returns the create requests of all rows, in order.
+func (s *MovieService) validateMovieImport(ctx context.Context, tx pgx.Tx, rows []movieImportRow) ([]dto.CreateMovieRequest, error) {
+	genreIDs, err := genreIDsByName(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]int{}
+	requests := make([]dto.CreateMovieRequest, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		movie := row.movie
+		req := dto.CreateMovieRequest{
+			Title:             strings.TrimSpace(movie.Title),
+			Overview:          strings.TrimSpace(movie.Overview),
+			Duration:          movie.Duration,
+			Director:          strings.Join(strings.Fields(movie.Director), " "),
+			Cast:              movie.Cast,
+			AgeRating:         strings.TrimSpace(movie.AgeRating),
+			ContentAdvisories: []string{},
+		}
+
+		if req.Title == "" {
+			row.errors = append(row.errors, "title is required")
+		}
+		if req.Overview == "" {
+			row.errors = append(row.errors, "overview is required")
+		}
+		if req.Duration < 1 {
+			row.errors = append(row.errors, "duration must be at least 1 minute")
+		}
+
+		releaseDate, err := time.Parse("2006-01-02", strings.TrimSpace(movie.ReleaseDate))
+		if err != nil {
+			row.errors = append(row.errors, "release_date must use YYYY-MM-DD format")
+		}
+		req.ReleaseDate = releaseDate
+
+		if req.AgeRating == "" {
+			req.AgeRating = utils.AgeRatingSU
+		}
+		if !utils.IsValidAgeRating(req.AgeRating) {
+			row.errors = append(row.errors, fmt.Sprintf("invalid age rating: %s", req.AgeRating))
+		}
+
+		for _, advisory := range movie.ContentAdvisories {
+			if !utils.IsValidContentAdvisory(advisory) {
+				row.errors = append(row.errors, fmt.Sprintf("invalid content advisory: %s", advisory))
+			}
+			req.ContentAdvisories = append(req.ContentAdvisories, advisory)
+		}
+
+		for _, genre := range movie.Genres {
+			id, ok := genreIDs[strings.ToLower(strings.TrimSpace(genre))]
+			if !ok {
+				row.errors = append(row.errors, fmt.Sprintf("unknown genre: %s", genre))
+				continue
+			}
+			if !slices.Contains(req.GenreIDs, id) {
+				req.GenreIDs = append(req.GenreIDs, id)
+			}
+		}
+
+		for _, entry := range movie.Cast {
+			if name, _ := parseCastEntry(entry); name == "" {
+				row.errors = append(row.errors, fmt.Sprintf("invalid cast entry: %q", entry))
+			}
+		}
+
+		if req.Title != "" && err == nil {
+			key := strings.ToLower(req.Title) + "|" + releaseDate.Format("2006-01-02")
+			if first, ok := seen[key]; ok {
+				row.errors = append(row.errors, fmt.Sprintf("duplicate of row %d", first))
+			} else {
+				seen[key] = row.row
+
+				var exists bool
+				err := tx.QueryRow(ctx, `
+					SELECT EXISTS (
+						SELECT 1 FROM movies
+						WHERE LOWER(title) = LOWER($1) AND release_date = $2 AND deleted_at IS NULL
+					)`,
+					req.Title, releaseDate).Scan(&exists)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check existing movies: %w", err)
+				}
+				if exists {
+					row.errors = append(row.errors, "movie already exists")
+				}
+			}
+		}
+
+		requests[i] = req
+	}
+
+	return requests, nil
+}
+
+func genreIDsByName(ctx context.Context, db pgx.Tx) (map[string]int, error) {
+	rows, err := db.Query(ctx, "SELECT id, name FROM genres")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get genres: %w", err)
+	}
+	defer rows.Close()
+
+	ids := map[string]int{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("failed to get genres: %w", err)
+		}
+		ids[strings.ToLower(name)] = id
+	}
+	return ids, rows.Err()
+}
+
+// parseMovieImportJSON decodes the array one movie at a time so an oversized
+// import is refused without reading it whole.
+func parseMovieImportJSON(r io.Reader) ([]movieImportRow, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON, expected an array of movies: %w", err)
+	}
+	if token != json.Delim('[') {
+		return nil, errors.New("invalid JSON, expected an array of movies")
+	}
+
+	rows := []movieImportRow{}
+	for decoder.More() {
+		if len(rows) == maxMovieImportRows {
+			return nil, errTooManyImportRows
+		}
+		var movie dto.MovieTransferRow
+		if err := decoder.Decode(&movie); err != nil {
+			return nil, fmt.Errorf("invalid JSON, expected an array of movies: %w", err)
+		}
+		rows = append(rows, movieImportRow{row: len(rows) + 1, movie: movie})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON, expected an array of movies: %w", err)
+	}
+	return rows, nil
+}
+
+func parseMovieImportCSV(r io.Reader) ([]movieImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isMovieTransferColumn(name) {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		columns[name] = i
+	}
+
+	rows := []movieImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		if len(rows) == maxMovieImportRows {
+			return nil, errTooManyImportRows
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return unescapeCSVCell(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+
+		row := movieImportRow{row: len(rows) + 1}
+		row.movie = dto.MovieTransferRow{
+			Title:             field("title"),
+			Overview:          field("overview"),
+			ReleaseDate:       field("release_date"),
+			Director:          field("director"),
+			Genres:            splitTransferList(field("genres")),
+			Cast:              splitTransferList(field("cast")),
+			AgeRating:         field("age_rating"),
+			ContentAdvisories: splitTransferList(field("content_advisories")),
+			PosterPath:        optionalString(field("poster_path")),
+			BackdropPath:      optionalString(field("backdrop_path")),
+		}
+		if duration := field("duration"); duration != "" {
+			if row.movie.Duration, err = strconv.Atoi(duration); err != nil {
+				row.errors = append(row.errors, "duration must be a number")
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ExportMovies streams the catalog to w in the import format, so an export can
+// be edited and imported again.
+func (s *MovieService) ExportMovies(ctx context.Context, format string, w io.Writer) error {
+	rows, err := s.db.Query(ctx, `
+		SELECT
+			m.title,
+			m.overview,
+			m.duration,
+			TO_CHAR(m.release_date, 'YYYY-MM-DD'),
+			COALESCE(TRIM(d.first_name || ' ' || d.last_name), ''),
+			COALESCE((
+				SELECT ARRAY_AGG(g.name ORDER BY g.name)
+				FROM movies_genres mg
+				JOIN genres g ON g.id = mg.genre_id
+				WHERE mg.movie_id = m.movie_id
+			), '{}'),
+			COALESCE((
+				SELECT ARRAY_AGG(TRIM(a.first_name || ' ' || a.last_name) || COALESCE(':' || mc.character_name, '')
+					ORDER BY mc.billing_order NULLS LAST, mc.id)
+				FROM movies_cast mc
+				JOIN actors a ON a.id = mc.actor_id
+				WHERE mc.movie_id = m.movie_id
+			), '{}'),
+			m.age_rating,
+			m.content_advisories,
+			m.poster_path,
+			m.backdrop_path
+		FROM movies m
+		LEFT JOIN directors d ON d.id = m.director_id
+		WHERE m.deleted_at IS NULL
+		ORDER BY m.movie_id`)
+	if err != nil {
+		return fmt.Errorf("failed to export movies: %w", err)
+	}
+	defer rows.Close()
+
+	var csvWriter *csv.Writer
+	if format == MovieTransferCSV {
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(movieTransferColumns); err != nil {
+			return err
+		}
+	} else if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	count := 0
+	for rows.Next() {
+		var movie dto.MovieTransferRow
+		err := rows.Scan(&movie.Title, &movie.Overview, &movie.Duration, &movie.ReleaseDate, &movie.Director,
+			&movie.Genres, &movie.Cast, &movie.AgeRating, &movie.ContentAdvisories, &movie.PosterPath, &movie.BackdropPath)
+		if err != nil {
+			return fmt.Errorf("failed to export movies: %w", err)
+		}
+
+		if csvWriter != nil {
+			err = csvWriter.Write([]string{
+				escapeCSVCell(movie.Title),
+				escapeCSVCell(movie.Overview),
+				strconv.Itoa(movie.Duration),
+				movie.ReleaseDate,
+				escapeCSVCell(movie.Director),
+				escapeCSVCell(strings.Join(movie.Genres, movieTransferListSep)),
+				escapeCSVCell(strings.Join(movie.Cast, movieTransferListSep)),
+				escapeCSVCell(movie.AgeRating),
+				escapeCSVCell(strings.Join(movie.ContentAdvisories, movieTransferListSep)),
+				escapeCSVCell(derefString(movie.PosterPath)),
+				escapeCSVCell(derefString(movie.BackdropPath)),
+			})
+			if count%100 == 0 {
+				csvWriter.Flush()
+			}
+		} else {
+			err = writeJSONArrayItem(w, movie, count == 0)
+		}
+		if err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export movies: %w", err)
+	}
+
+	if csvWriter != nil {
+		csvWriter.Flush()
+		return csvWriter.Error()
+	}
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// escapeCSVCell prefixes cells a spreadsheet would evaluate as a formula with
+// a quote, so an exported title cannot run code when the file is opened.
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVCell reverses escapeCSVCell, letting an export be imported again.
+func unescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func writeJSONArrayItem(w io.Writer, item any, first bool) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if !first {
+		if _, err := io.WriteString(w, ",\n"); err != nil {
+			return err
+		}
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func isMovieTransferColumn(name string) bool {
+	return slices.Contains(movieTransferColumns, name)
+}
+
+func splitTransferList(value string) []string {
+	values := []string{}
+	for _, part := range strings.Split(value, movieTransferListSep) {
+		if part = strings.TrimSpace(part); part != "" {
+			values = append(values, part)
+		}
+	}
+	return values
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEscapeCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "Inception", want: "Inception"},
+		{value: "", want: ""},
+		{value: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{value: "+1", want: "'+1"},
+		{value: "-2+3", want: "'-2+3"},
+		{value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{value: "\tcmd", want: "'\tcmd"},
+		{value: "\rcmd", want: "'\rcmd"},
+		{value: "'quoted", want: "'quoted"},
+	}
+
+	for _, tt := range tests {
+		got := escapeCSVCell(tt.value)
+		if got != tt.want {
+			t.Errorf("escapeCSVCell(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+		if back := unescapeCSVCell(got); back != tt.value {
+			t.Errorf("unescapeCSVCell(%q) = %q, want %q", got, back, tt.value)
+		}
+	}
+}
+
+func TestParseMovieImportRowLimit(t *testing.T) {
+	csvImport := func(n int) string {
+		var b strings.Builder
+		b.WriteString("title,duration\n")
+		for i := range n {
+			fmt.Fprintf(&b, "Movie %d,90\n", i)
+		}
+		return b.String()
+	}
+	jsonImport := func(n int) string {
+		items := make([]string, n)
+		for i := range items {
+			items[i] = fmt.Sprintf(`{"title":"Movie %d"}`, i)
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	}
+
+	tests := []struct {
+		name    string
+		parse   func(string) ([]movieImportRow, error)
+		build   func(int) string
+		rows    int
+		wantErr error
+	}{
+		{name: "csv at limit", parse: parseCSVString, build: csvImport, rows: maxMovieImportRows},
+		{name: "csv over limit", parse: parseCSVString, build: csvImport, rows: maxMovieImportRows + 1, wantErr: errTooManyImportRows},
+		{name: "json at limit", parse: parseJSONString, build: jsonImport, rows: maxMovieImportRows},
+		{name: "json over limit", parse: parseJSONString, build: jsonImport, rows: maxMovieImportRows + 1, wantErr: errTooManyImportRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tt.parse(tt.build(tt.rows))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if len(rows) != tt.rows {
+				t.Errorf("got %d rows, want %d", len(rows), tt.rows)
+			}
+		})
+	}
+}
+
+func TestParseMovieImportCSVUnescapesCells(t *testing.T) {
+	rows, err := parseCSVString("title,overview,duration\n'=Sum of Us,'-dash,90\n")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if rows[0].movie.Title != "=Sum of Us" || rows[0].movie.Overview != "-dash" || rows[0].movie.Duration != 90 {
+		t.Errorf("unexpected row: %+v", rows[0].movie)
+	}
+}
+
+func TestParseMovieImportJSONRejectsNonArray(t *testing.T) {
+	for _, body := range []string{`{"title":"Inception"}`, `[{"title":"Inception"}`, ``} {
+		if _, err := parseJSONString(body); err == nil {
+			t.Errorf("expected %q to be rejected", body)
+		}
+	}
+}
+
+func parseCSVString(s string) ([]movieImportRow, error) {
+	return parseMovieImportCSV(strings.NewReader(s))
+}
+
+func parseJSONString(s string) ([]movieImportRow, error) {
+	return parseMovieImportJSON(strings.NewReader(s))
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"noir-backend/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+type oidcLoginState struct {
+	CodeVerifier string `json:"code_verifier"`
+	Nonce        string `json:"nonce"`
+}
+
+// StartOIDCLogin prepares an authorization code request with PKCE and returns
+// the provider URL the client should be redirected to.
+func (s *AuthService) StartOIDCLogin(ctx context.Context) (string, int, error) {
+	cfg := utils.Load().OIDC
+	if !cfg.Enabled() {
+		return "", http.StatusNotFound, errors.New("oidc login is not configured")
+	}
+
+	discovery, err := utils.DiscoverOIDC(ctx, cfg.IssuerURL)
+	if err != nil {
+		return "", http.StatusBadGateway, err
+	}
+
+	verifier, challenge, err := utils.GeneratePKCE()
+	if err != nil {
+		return "", http.StatusInternalServerError, fmt.Errorf("failed to generate pkce: %w", err)
+	}
+	state, err := utils.RandomURLToken(24)
+	if err != nil {
+		return "", http.StatusInternalServerError, fmt.Errorf("failed to generate state: %w", err)
+	}
+	nonce, err := utils.RandomURLToken(24)
+	if err != nil {
+		return "", http.StatusInternalServerError, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	payload, _ := json.Marshal(oidcLoginState{CodeVerifier: verifier, Nonce: nonce})
+	err = s.redis.Set(ctx, fmt.Sprintf("oidc-state:%s", state), payload, oidcStateTTL).Err()
+	if err != nil {
+		return "", http.StatusInternalServerError, fmt.Errorf("failed to save login state: %w", err)
+	}
+
+	return utils.OIDCAuthorizationURL(discovery, cfg, state, nonce, challenge), http.StatusOK, nil
+}
+
+// CompleteOIDCLogin handles the provider callback. External identities are
+// linked to existing users by verified email, otherwise a new user is created.
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, code, state string) (*dto.AuthResponse, int, error) {
+	cfg := utils.Load().OIDC
+	if !cfg.Enabled() {
+		return nil, http.StatusNotFound, errors.New("oidc login is not configured")
+	}
+
+	stateKey := fmt.Sprintf("oidc-state:%s", state)
+	payload, err := s.redis.GetDel(ctx, stateKey).Bytes()
+	if err != nil {
+		return nil, http.StatusBadRequest, errors.New("invalid or expired login state")
+	}
+
+	var loginState oidcLoginState
+	if err := json.Unmarshal(payload, &loginState); err != nil {
+		return nil, http.StatusBadRequest, errors.New("invalid or expired login state")
+	}
+
+	discovery, err := utils.DiscoverOIDC(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+
+	token, err := utils.ExchangeOIDCCode(ctx, discovery, cfg, code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	claims, err := utils.VerifyOIDCIDToken(ctx, discovery, cfg.ClientID, token.IDToken, loginState.Nonce)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	user, status, err := s.linkOIDCIdentity(ctx, cfg.Provider, claims)
+	if err != nil {
+		return nil, status, err
+	}
+
+	if !user.IsActive {
+		return nil, http.StatusForbidden, errors.New("account disabled")
+	}
+
+	if user.TOTPEnabled {
+		challenge, err := s.createTwoFactorChallenge(ctx, user.UserID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+
+		return &dto.AuthResponse{
+			RequiresTwoFactor: true,
+			ChallengeToken:    challenge,
+		}, http.StatusOK, nil
+	}
+
+	response, err := s.completeLogin(ctx, user, false)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return response, http.StatusOK, nil
+}
+
+func (s *AuthService) linkOIDCIdentity(ctx context.Context, provider string, claims *utils.OIDCClaims) (*models.User, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	err = tx.QueryRow(ctx, `
+		UPDATE user_identities SET last_login_at = NOW()
+		WHERE provider = $1 AND subject = $2
+		RETURNING user_id`,
+		provider, claims.Subject).Scan(&userID)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		if claims.Email == "" || !claims.EmailVerified {
+			return nil, http.StatusForbidden, errors.New("identity provider did not return a verified email")
+		}
+
+		err = tx.QueryRow(ctx,
+			"SELECT user_id FROM users WHERE email = $1", claims.Email).Scan(&userID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			userID, err = createOIDCUser(ctx, tx, claims)
+			if err != nil {
+				return nil, http.StatusInternalServerError, err
+			}
+		} else if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to find user: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+			VALUES ($1, $2, $3, $4, NOW())`,
+			userID, provider, claims.Subject, claims.Email)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to link identity: %w", err)
+		}
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to find identity: %w", err)
+	}
+
+	user := &models.User{}
+	err = tx.QueryRow(ctx,
+		`SELECT user_id, email, role, is_active, totp_enabled, created_at, updated_at, last_login
+		FROM users WHERE user_id = $1`,
+		userID).Scan(&user.UserID, &user.Email, &user.Role, &user.IsActive, &user.TOTPEnabled, &user.CreatedAt, &user.UpdatedAt, &user.LastLogin)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return user, http.StatusOK, nil
+}
+
+func createOIDCUser(ctx context.Context, tx pgx.Tx, claims *utils.OIDCClaims) (int, error) {
+	// The account can only be reached through the identity provider until the
+	// user sets a password with the reset password flow.
+	randomPassword, err := utils.RandomURLToken(32)
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashedPassword, err := utils.HashPassword(randomPassword)
+	if err != nil {
+		return 0, err
+	}
+
+	var userID int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO users (email, password_hash, role)
+		VALUES ($1, $2, 'user')
+		RETURNING user_id`,
+		claims.Email, hashedPassword).Scan(&userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	firstName, lastName := claims.GivenName, claims.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName = utils.SplitFullName(claims.Name)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO profile (user_id, first_name, last_name)
+		VALUES ($1, $2, $3)`,
+		userID, firstName, lastName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create profile: %w", err)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	paymentEventCreated          = "created"
+	paymentEventPaid             = "paid"
+	paymentEventCancelled        = "cancelled"
+	paymentEventExpired          = "expired"
+	paymentEventConfirmationSent = "confirmation_sent"
+	paymentEventSharePaid        = "share_paid"
+)
+
+// recordPaymentEvent appends to the payment history of a transaction. actorID
+// is nil for events raised by the system, such as expiry.
+func recordPaymentEvent(ctx context.Context, db execer, transactionID int, event string, actorID *int, note string) error {
+	var notePtr *string
+	if note != "" {
+		notePtr = &note
+	}
+
+	_, err := db.Exec(ctx, `
+		INSERT INTO payment_events (transaction_id, event, actor_id, note)
+		VALUES ($1, $2, $3, $4)`,
+		transactionID, event, actorID, notePtr)
+	if err != nil {
+		return fmt.Errorf("failed to write payment event: %w", err)
+	}
+	return nil
+}
+
+// inCinemaScope reports whether cinemaID is within cinemaIDs, a nil scope
+// allowing every cinema.
+func inCinemaScope(cinemaIDs []int, cinemaID int) bool {
+	if cinemaIDs == nil {
+		return true
+	}
+	for _, id := range cinemaIDs {
+		if id == cinemaID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PeopleService struct {
+	db    *pgxpool.Pool
+	cache *Cache
+}
+
+func NewPeopleService(db *pgxpool.Pool, cache *Cache) *PeopleService {
+	return &PeopleService{db: db, cache: cache}
+}
+
+func (s *PeopleService) ListPeople(ctx context.Context, q string, limit, offset int) ([]dto.PersonListItem, int, error) {
+	where := ""
+	args := []any{}
+	if q = strings.TrimSpace(q); q != "" {
+		args = append(args, "%"+escapeLike(q)+"%")
+		where = "WHERE (first_name || ' ' || last_name) ILIKE $1"
+	}
+
+	var total int
+	err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM people "+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count people: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`
+		SELECT id, TRIM(first_name || ' ' || last_name), photo_path
+		FROM people
+		%s
+		ORDER BY first_name, last_name, id
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2),
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get people: %w", err)
+	}
+
+	people, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.PersonListItem, error) {
+		var p dto.PersonListItem
+		err := row.Scan(&p.ID, &p.Name, &p.PhotoPath)
+		return p, err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect people: %w", err)
+	}
+
+	return people, total, nil
+}
+
+// GetPerson returns a person with every movie they acted in or directed.
+func (s *PeopleService) GetPerson(ctx context.Context, personID int) (*dto.PersonResponse, int, error) {
+	rows, err := s.db.Query(ctx, "SELECT * FROM people WHERE id = $1", personID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get person: %w", err)
+	}
+	person, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Person])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("person not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get person: %w", err)
+	}
+
+	rows, err = s.db.Query(ctx, `
+		SELECT m.movie_id, m.title, m.poster_path, m.release_date, mc.character_name, mc.billing_order
+		FROM actors a
+		JOIN movies_cast mc ON mc.actor_id = a.id
+		JOIN movies m ON m.movie_id = mc.movie_id
+		WHERE a.person_id = $1 AND m.deleted_at IS NULL
+		ORDER BY m.release_date DESC`,
+		personID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get acting credits: %w", err)
+	}
+	acting, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.ActingCredit, error) {
+		var credit dto.ActingCredit
+		err := row.Scan(&credit.MovieID, &credit.Title, &credit.PosterPath, &credit.ReleaseDate, &credit.CharacterName, &credit.BillingOrder)
+		return credit, err
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to collect acting credits: %w", err)
+	}
+
+	rows, err = s.db.Query(ctx, `
+		SELECT m.movie_id, m.title, m.poster_path, m.release_date
+		FROM directors d
+		JOIN movies m ON m.director_id = d.id
+		WHERE d.person_id = $1 AND m.deleted_at IS NULL
+		ORDER BY m.release_date DESC`,
+		personID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get directing credits: %w", err)
+	}
+	directing, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.DirectingCredit, error) {
+		var credit dto.DirectingCredit
+		err := row.Scan(&credit.MovieID, &credit.Title, &credit.PosterPath, &credit.ReleaseDate)
+		return credit, err
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to collect directing credits: %w", err)
+	}
+
+	return &dto.PersonResponse{
+		ID:        person.ID,
+		FirstName: person.FirstName,
+		LastName:  person.LastName,
+		Bio:       person.Bio,
+		PhotoPath: person.PhotoPath,
+		BirthDate: person.BirthDate,
+		Filmography: dto.Filmography{
+			Acting:    acting,
+			Directing: directing,
+		},
+	}, http.StatusOK, nil
+}
+
+func (s *PeopleService) CreatePerson(ctx context.Context, actorID int, req dto.CreatePersonRequest, photoPath *string) (*models.Person, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO people (first_name, last_name, bio, photo_path, birth_date)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *`,
+		strings.TrimSpace(req.FirstName), strings.TrimSpace(req.LastName), req.Bio, photoPath, req.BirthDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create person: %w", err)
+	}
+
+	person, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Person])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create person: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "person.created",
+		EntityType: "person",
+		EntityID:   auditEntityID(person.ID),
+		Changes:    auditDiff(nil, personAuditState(person)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction")
+	}
+
+	return &person, nil
+}
+
+// UpdatePerson edits a person. Name changes are copied to their actor and
+// director records so movie listings and search show the new name.
+func (s *PeopleService) UpdatePerson(ctx context.Context, actorID, personID int, req dto.UpdatePersonRequest, photoPath *string) (*models.Person, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "SELECT * FROM people WHERE id = $1 FOR UPDATE", personID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get person: %w", err)
+	}
+	before, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Person])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("person not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get person: %w", err)
+	}
+
+	rows, err = tx.Query(ctx, `
+		UPDATE people SET
+			first_name = COALESCE($2, first_name),
+			last_name = COALESCE($3, last_name),
+			bio = COALESCE($4, bio),
+			photo_path = COALESCE($5, photo_path),
+			birth_date = COALESCE($6, birth_date),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING *`,
+		personID, trimmed(req.FirstName), trimmed(req.LastName), req.Bio, photoPath, req.BirthDate)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update person: %w", err)
+	}
+	person, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Person])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("person not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update person: %w", err)
+	}
+
+	if req.FirstName != nil || req.LastName != nil {
+		_, err = tx.Exec(ctx,
+			"UPDATE actors SET first_name = $2, last_name = $3 WHERE person_id = $1",
+			personID, person.FirstName, person.LastName)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to update actor: %w", err)
+		}
+
+		_, err = tx.Exec(ctx,
+			"UPDATE directors SET first_name = $2, last_name = $3 WHERE person_id = $1",
+			personID, person.FirstName, person.LastName)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to update director: %w", err)
+		}
+
+		// Touch the movies so the search vector trigger picks up the new name.
+		_, err = tx.Exec(ctx, `
+			UPDATE movies SET title = title
+			WHERE movie_id IN (
+				SELECT mc.movie_id FROM movies_cast mc
+				JOIN actors a ON a.id = mc.actor_id
+				WHERE a.person_id = $1
+			)`,
+			personID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to refresh search index: %w", err)
+		}
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "person.updated",
+		EntityType: "person",
+		EntityID:   auditEntityID(personID),
+		Changes:    auditDiff(personAuditState(before), personAuditState(person)),
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return &person, http.StatusOK, nil
+}
+
+// DeletePerson removes a person with their cast entries. Movies they directed
+// are kept without a director.
+func (s *PeopleService) DeletePerson(ctx context.Context, actorID, personID int) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, "SELECT * FROM people WHERE id = $1 FOR UPDATE", personID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get person: %w", err)
+	}
+	person, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Person])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, errors.New("person not found")
+	} else if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get person: %w", err)
+	}
+
+	rows, err = tx.Query(ctx, `
+		UPDATE movies SET director_id = NULL
+		WHERE director_id IN (SELECT id FROM directors WHERE person_id = $1)
+		RETURNING movie_id`,
+		personID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to unlink directed movies: %w", err)
+	}
+	unlinked, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to unlink directed movies: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, "DELETE FROM people WHERE id = $1", personID); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete person: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "person.deleted",
+		EntityType: "person",
+		EntityID:   auditEntityID(personID),
+		Metadata:   map[string]any{"movies_without_director": unlinked},
+		Changes:    auditDiff(personAuditState(person), nil),
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return http.StatusOK, nil
+}
+
+func personAuditState(person models.Person) map[string]any {
+	state := map[string]any{
+		"first_name": person.FirstName,
+		"last_name":  person.LastName,
+		"bio":        person.Bio,
+		"photo_path": person.PhotoPath,
+		"birth_date": nil,
+	}
+	if person.BirthDate != nil {
+		state["birth_date"] = person.BirthDate.Format("2006-01-02")
+	}
+	return state
+}
+
+func trimmed(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*s)
+	return &t
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"noir-backend/dto"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	ReportByMovie         = "movie"
+	ReportByCinema        = "cinema"
+	ReportByShowtime      = "showtime"
+	ReportByDay           = "day"
+	ReportByPaymentMethod = "payment_method"
+)
+
+// salesReportGroups maps a grouping to its key and label over the
+// per_showtime and cinema/movie columns of the sales report query.
+var salesReportGroups = map[string]struct{ key, label, order string }{
+	ReportByMovie:    {"m.movie_id::text", "m.title", "revenue DESC, label"},
+	ReportByCinema:   {"c.id::text", "c.name", "revenue DESC, label"},
+	ReportByShowtime: {"ps.showtime_id::text", "m.title || ' - ' || c.name || ' ' || TO_CHAR(ps.show_datetime, 'YYYY-MM-DD HH24:MI')", "MIN(ps.show_datetime), key"},
+	ReportByDay:      {"TO_CHAR(ps.show_datetime, 'YYYY-MM-DD')", "TO_CHAR(ps.show_datetime, 'Dy DD Mon YYYY')", "key"},
+}
+
+var salesReportColumns = []string{
+	"key", "label", "revenue", "tickets_sold", "capacity", "occupancy_rate",
+	"paid_transactions", "cancelled_transactions", "cancellation_rate",
+}
+
+// transactionShowtimes gives every transaction the showtime its tickets are
+// for, a booking never spans several showtimes.
+const transactionShowtimes = `
+	sales AS (
+		SELECT t.transaction_id, t.status, t.total_amount, t.total_seats, t.payment_method_id, ts.showtime_id
+		FROM transactions t
+		JOIN LATERAL (
+			SELECT tk.showtime_id FROM tickets tk WHERE tk.transaction_id = t.transaction_id LIMIT 1
+		) ts ON true
+	)`
+
+type ReportService struct {
+	db    *pgxpool.Pool
+	cache *Cache
+}
+
+func NewReportService(db *pgxpool.Pool, cache *Cache) *ReportService {
+	return &ReportService{db: db, cache: cache}
+}
+
+func IsValidReportGroup(groupBy string) bool {
+	_, ok := salesReportGroups[groupBy]
+	return ok || groupBy == ReportByPaymentMethod
+}
+
+// SalesReport aggregates bookings of the showtimes screened between
+// filter.From and filter.To. Revenue and tickets count paid transactions,
+// occupancy compares them with the seats of every showtime in the group and
+// the cancellation rate is the share of cancelled transactions among paid and
+// cancelled ones. Occupancy is left empty when grouping by payment method.
+func (s *ReportService) SalesReport(ctx context.Context, filter dto.SalesReportFilter) (*dto.SalesReport, error) {
+	args := []any{filter.From, filter.To}
+	conditions := []string{"s.show_datetime >= $1", "s.show_datetime < $2"}
+	if filter.MovieID != 0 {
+		args = append(args, filter.MovieID)
+		conditions = append(conditions, fmt.Sprintf("s.movie_id = $%d", len(args)))
+	}
+	if filter.CinemaID != 0 {
+		args = append(args, filter.CinemaID)
+		conditions = append(conditions, fmt.Sprintf("s.cinema_id = $%d", len(args)))
+	}
+	if filter.CinemaIDs != nil {
+		args = append(args, filter.CinemaIDs)
+		conditions = append(conditions, fmt.Sprintf("s.cinema_id = ANY($%d)", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var query string
+	if filter.GroupBy == ReportByPaymentMethod {
+		query = fmt.Sprintf(`
+			WITH %s
+			SELECT COALESCE(pm.payment_method_id::text, ''), COALESCE(pm.name, 'Unknown') AS label,
+			       COALESCE(SUM(sa.total_amount) FILTER (WHERE sa.status = 'paid'), 0)::float8 AS revenue,
+			       COALESCE(SUM(sa.total_seats) FILTER (WHERE sa.status = 'paid'), 0)::int,
+			       NULL::int,
+			       COUNT(*) FILTER (WHERE sa.status = 'paid')::int,
+			       COUNT(*) FILTER (WHERE sa.status = 'cancelled')::int
+			FROM sales sa
+			JOIN showtimes s ON s.showtime_id = sa.showtime_id
+			LEFT JOIN payment_method pm ON pm.payment_method_id = sa.payment_method_id
+			WHERE %s
+			GROUP BY pm.payment_method_id, pm.name
+			ORDER BY revenue DESC, label`, transactionShowtimes, where)
+	} else {
+		group := salesReportGroups[filter.GroupBy]
+		query = fmt.Sprintf(`
+			WITH %s,
+			per_showtime AS (
+				SELECT s.showtime_id, s.movie_id, s.cinema_id, s.show_datetime, c.total_seats AS capacity,
+				       COALESCE(SUM(sa.total_amount) FILTER (WHERE sa.status = 'paid'), 0) AS revenue,
+				       COALESCE(SUM(sa.total_seats) FILTER (WHERE sa.status = 'paid'), 0) AS tickets_sold,
+				       COUNT(sa.transaction_id) FILTER (WHERE sa.status = 'paid') AS paid,
+				       COUNT(sa.transaction_id) FILTER (WHERE sa.status = 'cancelled') AS cancelled
+				FROM showtimes s
+				JOIN cinemas c ON c.id = s.cinema_id
+				LEFT JOIN sales sa ON sa.showtime_id = s.showtime_id
+				WHERE %s
+				GROUP BY s.showtime_id, c.total_seats
+			)
+			SELECT %s AS key, %s AS label,
+			       SUM(ps.revenue)::float8 AS revenue,
+			       SUM(ps.tickets_sold)::int,
+			       SUM(ps.capacity)::int,
+			       SUM(ps.paid)::int,
+			       SUM(ps.cancelled)::int
+			FROM per_showtime ps
+			JOIN movies m ON m.movie_id = ps.movie_id
+			JOIN cinemas c ON c.id = ps.cinema_id
+			GROUP BY 1, 2
+			ORDER BY %s`, transactionShowtimes, where, group.key, group.label, group.order)
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sales report: %w", err)
+	}
+	reportRows, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.SalesReportRow, error) {
+		var r dto.SalesReportRow
+		err := row.Scan(&r.Key, &r.Label, &r.Revenue, &r.TicketsSold, &r.Capacity, &r.PaidTransactions, &r.CancelledTransactions)
+		return r, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect sales report: %w", err)
+	}
+
+	report := &dto.SalesReport{
+		GroupBy: filter.GroupBy,
+		From:    filter.From.Format("2006-01-02"),
+		To:      filter.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		Totals:  dto.SalesReportRow{Key: "total", Label: "Total"},
+		Rows:    reportRows,
+	}
+	if filter.GroupBy != ReportByPaymentMethod {
+		report.Totals.Capacity = new(int)
+	}
+	for i := range report.Rows {
+		row := &report.Rows[i]
+		setSalesRates(row)
+
+		report.Totals.Revenue += row.Revenue
+		report.Totals.TicketsSold += row.TicketsSold
+		report.Totals.PaidTransactions += row.PaidTransactions
+		report.Totals.CancelledTransactions += row.CancelledTransactions
+		if row.Capacity != nil && report.Totals.Capacity != nil {
+			*report.Totals.Capacity += *row.Capacity
+		}
+	}
+	report.Totals.Revenue = roundAmount(report.Totals.Revenue)
+	setSalesRates(&report.Totals)
+
+	return report, nil
+}
+
+// WriteSalesReportCSV writes the report rows followed by the totals.
+func WriteSalesReportCSV(w io.Writer, report *dto.SalesReport) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(salesReportColumns); err != nil {
+		return err
+	}
+
+	for _, row := range append(report.Rows, report.Totals) {
+		capacity, occupancy := "", ""
+		if row.Capacity != nil {
+			capacity = strconv.Itoa(*row.Capacity)
+		}
+		if row.OccupancyRate != nil {
+			occupancy = strconv.FormatFloat(*row.OccupancyRate, 'f', 2, 64)
+		}
+		err := csvWriter.Write([]string{
+			row.Key,
+			row.Label,
+			strconv.FormatFloat(row.Revenue, 'f', 2, 64),
+			strconv.Itoa(row.TicketsSold),
+			capacity,
+			occupancy,
+			strconv.Itoa(row.PaidTransactions),
+			strconv.Itoa(row.CancelledTransactions),
+			strconv.FormatFloat(row.CancellationRate, 'f', 2, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func setSalesRates(row *dto.SalesReportRow) {
+	if row.Capacity != nil && *row.Capacity > 0 {
+		occupancy := roundAmount(float64(row.TicketsSold) * 100 / float64(*row.Capacity))
+		row.OccupancyRate = &occupancy
+	}
+	if settled := row.PaidTransactions + row.CancelledTransactions; settled > 0 {
+		row.CancellationRate = roundAmount(float64(row.CancelledTransactions) * 100 / float64(settled))
+	}
+}
+
+func roundAmount(value float64) float64 {
+	return math.Round(value*100) / 100
+}
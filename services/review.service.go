@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var reviewSortOrders = map[string]string{
+	"newest":  "r.created_at DESC",
+	"oldest":  "r.created_at ASC",
+	"highest": "r.rating DESC, r.created_at DESC",
+	"lowest":  "r.rating ASC, r.created_at DESC",
+}
+
+func IsValidReviewSort(sort string) bool {
+	_, ok := reviewSortOrders[sort]
+	return ok
+}
+
+type ReviewService struct {
+	db    *pgxpool.Pool
+	cache *Cache
+}
+
+func NewReviewService(db *pgxpool.Pool, cache *Cache) *ReviewService {
+	return &ReviewService{db: db, cache: cache}
+}
+
+const reviewSelect = `
+	SELECT r.id, r.movie_id, r.user_id,
+	       COALESCE(NULLIF(TRIM(CONCAT_WS(' ', p.first_name, LEFT(p.last_name, 1))), ''), 'Anonymous'),
+	       r.rating, r.body, r.is_verified, r.status, r.moderation_reason, r.moderated_at,
+	       r.created_at, r.updated_at
+	FROM reviews r
+	LEFT JOIN profile p ON p.user_id = r.user_id`
+
+func scanReview(row pgx.CollectableRow) (dto.ReviewResponse, error) {
+	var r dto.ReviewResponse
+	err := row.Scan(&r.ID, &r.MovieID, &r.UserID, &r.ReviewerName, &r.Rating, &r.Body, &r.IsVerified,
+		&r.Status, &r.ModerationReason, &r.ModeratedAt, &r.CreatedAt, &r.UpdatedAt)
+	return r, err
+}
+
+// ListReviews returns reviews matching filter. An empty filter status lists
+// reviews in every moderation state.
+func (s *ReviewService) ListReviews(ctx context.Context, filter dto.ReviewFilter, limit, offset int) ([]dto.ReviewResponse, int, error) {
+	orderBy, ok := reviewSortOrders[filter.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid sort: %s", filter.Sort)
+	}
+
+	conditions := []string{}
+	args := []any{}
+	if filter.MovieID != nil {
+		args = append(args, *filter.MovieID)
+		conditions = append(conditions, fmt.Sprintf("r.movie_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("r.status = $%d", len(args)))
+	}
+	if filter.VerifiedOnly {
+		conditions = append(conditions, "r.is_verified = true")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := s.db.QueryRow(ctx, "SELECT COUNT(*) FROM reviews r "+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`%s
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, reviewSelect, where, orderBy, len(args)+1, len(args)+2),
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	reviews, err := pgx.CollectRows(rows, scanReview)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect reviews: %w", err)
+	}
+
+	return reviews, total, nil
+}
+
+// SaveReview creates or replaces the user's review of a movie. Reviews of
+// users holding a used or paid ticket for the movie get the verified badge.
+func (s *ReviewService) SaveReview(ctx context.Context, userID, movieID int, req dto.ReviewRequest) (*dto.ReviewResponse, int, error) {
+	var releaseDate time.Time
+	err := s.db.QueryRow(ctx,
+		"SELECT release_date FROM movies WHERE movie_id = $1 AND deleted_at IS NULL", movieID).Scan(&releaseDate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("movie not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get movie: %w", err)
+	}
+	if releaseDate.After(time.Now()) {
+		return nil, http.StatusBadRequest, errors.New("movie has not been released yet")
+	}
+
+	var verified bool
+	err = s.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM tickets tk
+			JOIN transactions t ON t.transaction_id = tk.transaction_id
+			JOIN showtimes s ON s.showtime_id = tk.showtime_id
+			WHERE t.created_by = $1
+			  AND s.movie_id = $2
+			  AND (tk.status = 'used' OR (t.status = 'paid' AND tk.status != 'cancelled'))
+		)`, userID, movieID).Scan(&verified)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to check tickets: %w", err)
+	}
+
+	var body *string
+	if req.Body != nil {
+		if trimmedBody := strings.TrimSpace(*req.Body); trimmedBody != "" {
+			body = &trimmedBody
+		}
+	}
+
+	// A review hidden by a moderator stays hidden when its author edits it.
+	var reviewID int
+	err = s.db.QueryRow(ctx, `
+		INSERT INTO reviews (movie_id, user_id, rating, body, is_verified)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (movie_id, user_id) DO UPDATE SET
+			rating = EXCLUDED.rating,
+			body = EXCLUDED.body,
+			is_verified = EXCLUDED.is_verified,
+			updated_at = NOW()
+		RETURNING id`,
+		movieID, userID, req.Rating, body, verified).Scan(&reviewID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to save review: %w", err)
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return review, http.StatusOK, nil
+}
+
+func (s *ReviewService) DeleteOwnReview(ctx context.Context, userID, movieID int) (int, error) {
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM reviews WHERE movie_id = $1 AND user_id = $2", movieID, userID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete review: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return http.StatusNotFound, errors.New("review not found")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return http.StatusOK, nil
+}
+
+// ModerateReview publishes or hides a review. Hidden reviews are left out of
+// listings and ratings.
+func (s *ReviewService) ModerateReview(ctx context.Context, actorID, reviewID int, req dto.ModerateReviewRequest) (*dto.ReviewResponse, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var previous string
+	err = tx.QueryRow(ctx, `
+		UPDATE reviews r SET
+			status = $2,
+			moderated_by = $3,
+			moderated_at = NOW(),
+			moderation_reason = $4
+		FROM reviews old
+		WHERE r.id = $1 AND old.id = r.id
+		RETURNING old.status`,
+		reviewID, req.Status, actorID, req.Reason).Scan(&previous)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("review not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to moderate review: %w", err)
+	}
+
+	metadata := map[string]any{"from": previous, "to": req.Status}
+	if req.Reason != nil {
+		metadata["reason"] = *req.Reason
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "review." + req.Status,
+		EntityType: "review",
+		EntityID:   auditEntityID(reviewID),
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	review, err := s.getReview(ctx, reviewID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return review, http.StatusOK, nil
+}
+
+func (s *ReviewService) DeleteReview(ctx context.Context, actorID, reviewID int) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var review models.Review
+	err = tx.QueryRow(ctx,
+		"DELETE FROM reviews WHERE id = $1 RETURNING movie_id, user_id, rating", reviewID).
+		Scan(&review.MovieID, &review.UserID, &review.Rating)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, errors.New("review not found")
+	} else if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete review: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "review.deleted",
+		EntityType: "review",
+		EntityID:   auditEntityID(reviewID),
+		Metadata: map[string]any{
+			"movie_id": review.MovieID,
+			"user_id":  review.UserID,
+			"rating":   review.Rating,
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+	s.cache.Invalidate(ctx, catalogCacheNamespace)
+
+	return http.StatusOK, nil
+}
+
+func (s *ReviewService) getReview(ctx context.Context, reviewID int) (*dto.ReviewResponse, error) {
+	rows, err := s.db.Query(ctx, reviewSelect+" WHERE r.id = $1", reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	review, err := pgx.CollectOneRow(rows, scanReview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return &review, nil
+}
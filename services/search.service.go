@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"noir-backend/dto"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	suggestCacheTTL = 10 * time.Minute
+	// A prefix is cached once it was requested suggestHotThreshold times
+	// within suggestHitWindow.
+	suggestHotThreshold = 3
+	suggestHitWindow    = time.Hour
+)
+
+type SearchService struct {
+	db    *pgxpool.Pool
+	redis *redis.Client
+}
+
+func NewSearchService(db *pgxpool.Pool, redis *redis.Client) *SearchService {
+	return &SearchService{db: db, redis: redis}
+}
+
+// Suggest returns movies, actors and directors similar to q, ranked by
+// trigram similarity with prefix matches first.
+func (s *SearchService) Suggest(ctx context.Context, q string, limit int) ([]dto.Suggestion, error) {
+	q = strings.ToLower(strings.TrimSpace(q))
+	cacheKey := fmt.Sprintf("suggest:%d:%s", limit, q)
+
+	if cached, err := s.redis.Get(ctx, cacheKey).Bytes(); err == nil {
+		var suggestions []dto.Suggestion
+		if err := json.Unmarshal(cached, &suggestions); err == nil {
+			return suggestions, nil
+		}
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT type, id, label, poster_path, score
+		FROM (
+			SELECT 'movie' AS type, m.movie_id AS id, m.title AS label, m.poster_path,
+			       similarity(m.title, $1) + CASE WHEN m.title ILIKE $2 THEN 1 ELSE 0 END AS score
+			FROM movies m
+			WHERE m.deleted_at IS NULL AND (m.title % $1 OR m.title ILIKE $2)
+			UNION ALL
+			SELECT 'actor', a.person_id, a.first_name || ' ' || a.last_name, NULL,
+			       similarity(a.first_name || ' ' || a.last_name, $1)
+			       + CASE WHEN a.first_name || ' ' || a.last_name ILIKE $2 THEN 1 ELSE 0 END
+			FROM actors a
+			WHERE (a.first_name || ' ' || a.last_name) % $1 OR (a.first_name || ' ' || a.last_name) ILIKE $2
+			UNION ALL
+			SELECT 'director', d.person_id, d.first_name || ' ' || d.last_name, NULL,
+			       similarity(d.first_name || ' ' || d.last_name, $1)
+			       + CASE WHEN d.first_name || ' ' || d.last_name ILIKE $2 THEN 1 ELSE 0 END
+			FROM directors d
+			WHERE (d.first_name || ' ' || d.last_name) % $1 OR (d.first_name || ' ' || d.last_name) ILIKE $2
+		) suggestions
+		ORDER BY score DESC, label ASC
+		LIMIT $3`,
+		q, escapeLike(q)+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get suggestions: %w", err)
+	}
+
+	suggestions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.Suggestion, error) {
+		var sg dto.Suggestion
+		err := row.Scan(&sg.Type, &sg.ID, &sg.Label, &sg.PosterPath, &sg.Score)
+		return sg, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect suggestions: %w", err)
+	}
+
+	s.cacheHotPrefix(ctx, q, cacheKey, suggestions)
+
+	return suggestions, nil
+}
+
+func (s *SearchService) cacheHotPrefix(ctx context.Context, q, cacheKey string, suggestions []dto.Suggestion) {
+	hitsKey := fmt.Sprintf("suggest-hits:%s", q)
+	hits, err := s.redis.Incr(ctx, hitsKey).Result()
+	if err != nil {
+		log.Printf("failed to count suggest hits: %v", err)
+		return
+	}
+	if hits == 1 {
+		s.redis.Expire(ctx, hitsKey, suggestHitWindow)
+	}
+	if hits < suggestHotThreshold {
+		return
+	}
+
+	payload, err := json.Marshal(suggestions)
+	if err != nil {
+		return
+	}
+	if err := s.redis.Set(ctx, cacheKey, payload, suggestCacheTTL).Err(); err != nil {
+		log.Printf("failed to cache suggestions: %v", err)
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
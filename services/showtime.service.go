@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// showtimeTurnaround is kept free after every screening for cleaning and
+	// seating the next audience.
+	showtimeTurnaround = 15 * time.Minute
+	maxTemplateDays    = 92
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+type ShowtimeService struct {
+	db *pgxpool.Pool
+}
+
+func NewShowtimeService(db *pgxpool.Pool) *ShowtimeService {
+	return &ShowtimeService{db: db}
+}
+
+// scheduledShowtime is a showtime occupying its cinema from Start to End,
+// turnaround included.
+type scheduledShowtime struct {
+	ID    *int
+	Start time.Time
+	End   time.Time
+}
+
+// GenerateShowtimes expands a weekly template into showtimes between the start
+// and end dates. Slots in the past or overlapping another showtime of the
+// cinema are skipped and listed in the report. Show times are wall clock times
+// like show_datetime itself.
+func (s *ShowtimeService) GenerateShowtimes(ctx context.Context, actorID int, req dto.ShowtimeTemplateRequest) (*dto.ShowtimeGenerationReport, int, error) {
+	slots, err := expandShowtimeTemplate(req)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	// Locking the cinema serialises concurrent generations for it.
+	var totalSeats int
+	var isActive bool
+	err = tx.QueryRow(ctx,
+		"SELECT total_seats, COALESCE(is_active, true) FROM cinemas WHERE id = $1 FOR UPDATE",
+		req.CinemaID).Scan(&totalSeats, &isActive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("cinema not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get cinema: %w", err)
+	}
+	if !isActive {
+		return nil, http.StatusConflict, errors.New("cinema is not active")
+	}
+
+	var duration int
+	err = tx.QueryRow(ctx,
+		"SELECT duration FROM movies WHERE movie_id = $1 AND deleted_at IS NULL",
+		req.MovieID).Scan(&duration)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("movie not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get movie: %w", err)
+	}
+	length := time.Duration(duration)*time.Minute + showtimeTurnaround
+
+	// Existing showtimes starting a day before the range may still run into it.
+	rows, err := tx.Query(ctx, `
+		SELECT s.showtime_id, s.show_datetime, m.duration
+		FROM showtimes s
+		JOIN movies m ON m.movie_id = s.movie_id
+		WHERE s.cinema_id = $1 AND s.show_datetime >= $2 AND s.show_datetime < $3
+		ORDER BY s.show_datetime`,
+		req.CinemaID, slots[0].AddDate(0, 0, -1), slots[len(slots)-1].Add(length))
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get showtimes: %w", err)
+	}
+	scheduled, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (scheduledShowtime, error) {
+		var id, minutes int
+		var start time.Time
+		if err := row.Scan(&id, &start, &minutes); err != nil {
+			return scheduledShowtime{}, err
+		}
+		end := start.Add(time.Duration(minutes)*time.Minute + showtimeTurnaround)
+		return scheduledShowtime{ID: &id, Start: start, End: end}, nil
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to collect showtimes: %w", err)
+	}
+
+	report := &dto.ShowtimeGenerationReport{
+		DryRun:  req.DryRun,
+		Total:   len(slots),
+		Created: []dto.GeneratedShowtime{},
+		Skipped: []dto.SkippedShowtime{},
+	}
+	now := wallClock(time.Now())
+	for _, start := range slots {
+		if !start.After(now) {
+			report.Skipped = append(report.Skipped, dto.SkippedShowtime{ShowDatetime: start, Reason: "in the past"})
+			continue
+		}
+
+		slot := scheduledShowtime{Start: start, End: start.Add(length)}
+		if conflict := findShowtimeConflict(scheduled, slot); conflict != nil {
+			skipped := dto.SkippedShowtime{ShowDatetime: start, ConflictShowtimeID: conflict.ID}
+			if conflict.ID == nil {
+				skipped.Reason = "overlaps another generated showtime"
+			} else {
+				skipped.Reason = "overlaps an existing showtime"
+			}
+			report.Skipped = append(report.Skipped, skipped)
+			continue
+		}
+
+		if !req.DryRun {
+			var id int
+			err := tx.QueryRow(ctx, `
+				INSERT INTO showtimes (movie_id, cinema_id, show_datetime, price, available_seats, created_at)
+				VALUES ($1, $2, $3, $4, $5, NOW())
+				RETURNING showtime_id`,
+				req.MovieID, req.CinemaID, start, req.Price, totalSeats).Scan(&id)
+			if err != nil {
+				return nil, http.StatusInternalServerError, fmt.Errorf("failed to create showtime: %w", err)
+			}
+			slot.ID = &id
+		}
+		scheduled = append(scheduled, slot)
+		report.Created = append(report.Created, dto.GeneratedShowtime{ShowtimeID: slot.ID, ShowDatetime: start})
+	}
+
+	if req.DryRun || len(report.Created) == 0 {
+		return report, http.StatusOK, nil
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "showtime.generated",
+		EntityType: "cinema",
+		EntityID:   auditEntityID(req.CinemaID),
+		Metadata: map[string]any{
+			"movie_id":   req.MovieID,
+			"start_date": req.StartDate,
+			"end_date":   req.EndDate,
+			"price":      req.Price,
+			"created":    len(report.Created),
+			"skipped":    len(report.Skipped),
+		},
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	return report, http.StatusCreated, nil
+}
+
+// expandShowtimeTemplate lists every start time of the template in order.
+func expandShowtimeTemplate(req dto.ShowtimeTemplateRequest) ([]time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("start_date must be formatted as YYYY-MM-DD")
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, errors.New("end_date must be formatted as YYYY-MM-DD")
+	}
+	if endDate.Before(startDate) {
+		return nil, errors.New("end_date must not be before start_date")
+	}
+	if endDate.Sub(startDate) >= maxTemplateDays*24*time.Hour {
+		return nil, fmt.Errorf("a template can cover at most %d days", maxTemplateDays)
+	}
+
+	times := map[time.Weekday][]time.Duration{}
+	for day, clocks := range req.Times {
+		weekday, ok := weekdays[strings.ToLower(strings.TrimSpace(day))]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", day)
+		}
+		for _, clock := range clocks {
+			parsed, err := time.Parse("15:04", strings.TrimSpace(clock))
+			if err != nil {
+				return nil, fmt.Errorf("invalid time %q for %s, use HH:MM", clock, day)
+			}
+			times[weekday] = append(times[weekday], time.Duration(parsed.Hour())*time.Hour+time.Duration(parsed.Minute())*time.Minute)
+		}
+	}
+
+	slots := []time.Time{}
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		for _, offset := range times[date.Weekday()] {
+			slots = append(slots, date.Add(offset))
+		}
+	}
+	if len(slots) == 0 {
+		return nil, errors.New("template does not produce any showtime")
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Before(slots[j]) })
+
+	return slots, nil
+}
+
+func findShowtimeConflict(scheduled []scheduledShowtime, slot scheduledShowtime) *scheduledShowtime {
+	for i := range scheduled {
+		if slot.Start.Before(scheduled[i].End) && scheduled[i].Start.Before(slot.End) {
+			return &scheduled[i]
+		}
+	}
+	return nil
+}
+
+// wallClock drops the location of t, the way show_datetime is stored.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
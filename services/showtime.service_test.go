@@ -0,0 +1,117 @@
+package services
+
+import (
+	"noir-backend/dto"
+	"testing"
+	"time"
+)
+
+func TestExpandShowtimeTemplate(t *testing.T) {
+	at := func(s string) time.Time {
+		parsed, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name    string
+		req     dto.ShowtimeTemplateRequest
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "weekdays across one week",
+			// 2025-08-01 is a Friday.
+			req: dto.ShowtimeTemplateRequest{
+				StartDate: "2025-08-01",
+				EndDate:   "2025-08-07",
+				Times: map[string][]string{
+					"friday":   {"21:00", "13:30"},
+					" Monday ": {"19:00"},
+				},
+			},
+			want: []string{"2025-08-01 13:30", "2025-08-01 21:00", "2025-08-04 19:00"},
+		},
+		{
+			name: "single day",
+			req: dto.ShowtimeTemplateRequest{
+				StartDate: "2025-08-02",
+				EndDate:   "2025-08-02",
+				Times:     map[string][]string{"saturday": {"10:00"}},
+			},
+			want: []string{"2025-08-02 10:00"},
+		},
+		{
+			name: "no matching weekday",
+			req: dto.ShowtimeTemplateRequest{
+				StartDate: "2025-08-02",
+				EndDate:   "2025-08-02",
+				Times:     map[string][]string{"monday": {"10:00"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown weekday",
+			req: dto.ShowtimeTemplateRequest{
+				StartDate: "2025-08-01",
+				EndDate:   "2025-08-07",
+				Times:     map[string][]string{"funday": {"10:00"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid time",
+			req: dto.ShowtimeTemplateRequest{
+				StartDate: "2025-08-01",
+				EndDate:   "2025-08-07",
+				Times:     map[string][]string{"friday": {"9pm"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "end before start",
+			req:     dto.ShowtimeTemplateRequest{StartDate: "2025-08-07", EndDate: "2025-08-01"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid date",
+			req:     dto.ShowtimeTemplateRequest{StartDate: "01/08/2025", EndDate: "2025-08-07"},
+			wantErr: true,
+		},
+		{
+			name: "longer than the maximum span",
+			req: dto.ShowtimeTemplateRequest{
+				StartDate: "2025-01-01",
+				EndDate:   "2025-12-31",
+				Times:     map[string][]string{"friday": {"10:00"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandShowtimeTemplate(tt.req)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandShowtimeTemplate: %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d slots %v, want %d", len(got), got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !got[i].Equal(at(want)) {
+					t.Errorf("slot %d = %s, want %s", i, got[i], want)
+				}
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"net/http"
 	"noir-backend/dto"
 	"noir-backend/models"
 	"time"
@@ -28,20 +31,67 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req dto.Crea
 	}
 	defer tx.Rollback(ctx)
 
+	expiresAt := time.Now().Add(5 * time.Minute) // 5 minutes to complete payment
+	transaction, tickets, err := s.reserveSeats(ctx, tx, req, userID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordPaymentEvent(ctx, tx, transaction.TransactionID, paymentEventCreated, &userID, ""); err != nil {
+		return nil, err
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &userID,
+		Action:     "transaction.created",
+		EntityType: "transaction",
+		EntityID:   auditEntityID(transaction.TransactionID),
+		Metadata: map[string]any{
+			"transaction_code": transaction.TransactionCode,
+			"showtime_id":      req.ShowtimeID,
+			"seats":            req.SeatNumbers,
+			"total_amount":     transaction.TotalAmount,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	transactionResponse := toTransactionResponse(transaction)
+	ticketResponse := toTicketResponse(tickets)
+
+	return &dto.TransactionResult{
+		Transaction: transactionResponse,
+		Tickets:     ticketResponse,
+	}, nil
+}
+
+// reserveSeats books req.SeatNumbers under a new pending transaction that
+// holds them until expiresAt.
+func (s *TransactionService) reserveSeats(ctx context.Context, tx pgx.Tx, req dto.CreateTransactionRequest, userID int, expiresAt time.Time) (models.Transaction, []models.Ticket, error) {
 	var showtime models.Showtime
-	err = tx.QueryRow(ctx, `
-		SELECT showtime_id, movie_id, cinema_id, show_datetime, price, available_seats, created_at 
-		FROM showtimes 
-		WHERE showtime_id = $1`,
+	var ageRating string
+	err := tx.QueryRow(ctx, `
+		SELECT s.showtime_id, s.movie_id, s.cinema_id, s.show_datetime, s.price, s.available_seats, s.created_at, m.age_rating
+		FROM showtimes s
+		JOIN movies m ON m.movie_id = s.movie_id
+		WHERE s.showtime_id = $1 AND m.deleted_at IS NULL`,
 		req.ShowtimeID).Scan(
 		&showtime.ShowtimeID, &showtime.MovieID, &showtime.CinemaID,
-		&showtime.ShowDatetime, &showtime.Price, &showtime.AvailableSeats, &showtime.CreatedAt)
+		&showtime.ShowDatetime, &showtime.Price, &showtime.AvailableSeats, &showtime.CreatedAt, &ageRating)
 	if err != nil {
-		return nil, fmt.Errorf("showtime not found: %w", err)
+		return models.Transaction{}, nil, fmt.Errorf("showtime not found: %w", err)
+	}
+
+	if err := s.checkAgeRestriction(ctx, tx, userID, ageRating, showtime.ShowDatetime, req.AgeAcknowledged); err != nil {
+		return models.Transaction{}, nil, err
 	}
 
 	if len(req.SeatNumbers) > showtime.AvailableSeats {
-		return nil, fmt.Errorf("not enough available seats")
+		return models.Transaction{}, nil, fmt.Errorf("not enough available seats")
 	}
 
 	bookedSeats := make([]string, 0)
@@ -50,20 +100,20 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req dto.Crea
 		WHERE showtime_id = $1 AND seat_number = ANY($2) AND status != 'cancelled'`,
 		req.ShowtimeID, req.SeatNumbers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check seat availability: %w", err)
+		return models.Transaction{}, nil, fmt.Errorf("failed to check seat availability: %w", err)
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var seat string
 		if err := rows.Scan(&seat); err != nil {
-			return nil, fmt.Errorf("failed to scan booked seat: %w", err)
+			return models.Transaction{}, nil, fmt.Errorf("failed to scan booked seat: %w", err)
 		}
 		bookedSeats = append(bookedSeats, seat)
 	}
 
 	if len(bookedSeats) > 0 {
-		return nil, fmt.Errorf("seats already booked: %v", bookedSeats)
+		return models.Transaction{}, nil, fmt.Errorf("seats already booked: %v", bookedSeats)
 	}
 
 	var paymentMethod models.PaymentMethod
@@ -75,12 +125,11 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req dto.Crea
 		&paymentMethod.PaymentMethodID, &paymentMethod.Name,
 		&paymentMethod.Code, &paymentMethod.IsActive)
 	if err != nil {
-		return nil, fmt.Errorf("payment method not found or inactive: %w", err)
+		return models.Transaction{}, nil, fmt.Errorf("payment method not found or inactive: %w", err)
 	}
 
 	transactionCode := utils.GenerateTransactionCode()
 	totalAmount := showtime.Price * float64(len(req.SeatNumbers))
-	expiresAt := time.Now().Add(5 * time.Minute) // 5 minutes to complete payment
 
 	rows, err = tx.Query(ctx, `
 		INSERT INTO transactions (
@@ -90,16 +139,16 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req dto.Crea
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING transaction_id, transaction_code, recipient_email, recipient_full_name, 
 		        recipient_phone_number, total_seats, total_amount, status, 
-		        created_at, expires_at, created_by, payment_method_id`,
+		        created_at, expires_at, paid_at, created_by, payment_method_id`,
 		transactionCode, req.RecipientEmail, req.RecipientFullName,
 		req.RecipientPhone, len(req.SeatNumbers), totalAmount, "pending",
 		time.Now(), expiresAt, userID, req.PaymentMethodID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+		return models.Transaction{}, nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 	transaction, err := pgx.CollectOneRow[models.Transaction](rows, pgx.RowToStructByName)
 	if err != nil {
-		return nil, fmt.Errorf("")
+		return models.Transaction{}, nil, fmt.Errorf("failed to read created transaction: %w", err)
 	}
 
 	tickets := make([]models.Ticket, 0, len(req.SeatNumbers))
@@ -114,7 +163,7 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req dto.Crea
 			&ticket.TicketID, &ticket.TicketCode, &ticket.ShowtimeID, &ticket.SeatNumber,
 			&ticket.Status, &ticket.TransactionID, &ticket.CreatedAt)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create ticket for seat %s: %w", seatNumber, err)
+			return models.Transaction{}, nil, fmt.Errorf("failed to create ticket for seat %s: %w", seatNumber, err)
 		}
 		tickets = append(tickets, ticket)
 	}
@@ -125,20 +174,10 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req dto.Crea
 		WHERE showtime_id = $2`,
 		len(req.SeatNumbers), req.ShowtimeID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update available seats: %w", err)
+		return models.Transaction{}, nil, fmt.Errorf("failed to update available seats: %w", err)
 	}
 
-	if err = tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	transactionResponse := toTransactionResponse(transaction)
-	ticketResponse := toTicketResponse(tickets)
-
-	return &dto.TransactionResult{
-		Transaction: transactionResponse,
-		Tickets:     ticketResponse,
-	}, nil
+	return transaction, tickets, nil
 }
 
 func (s *TransactionService) ProcessPayment(ctx context.Context, req dto.ProcessPaymentRequest) (*dto.TransactionResult, error) {
@@ -153,7 +192,8 @@ func (s *TransactionService) ProcessPayment(ctx context.Context, req dto.Process
 		       recipient_phone_number, total_seats, total_amount, status, 
 		       created_at, expires_at, paid_at, created_by, payment_method_id
 		FROM transactions 
-		WHERE transaction_code = $1`,
+		WHERE transaction_code = $1
+		FOR UPDATE`,
 		req.TransactionCode)
 	if err != nil {
 		return nil, fmt.Errorf("transaction not found: %w", err)
@@ -168,8 +208,25 @@ func (s *TransactionService) ProcessPayment(ctx context.Context, req dto.Process
 		return nil, fmt.Errorf("transaction is not pending")
 	}
 
+	var isGroup bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM group_booking_shares WHERE transaction_id = $1)",
+		transaction.TransactionID).Scan(&isGroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check group booking: %w", err)
+	}
+	if isGroup {
+		return nil, fmt.Errorf("group bookings are paid per share")
+	}
+
+	if err := checkMovieBookable(ctx, tx, transaction.TransactionID); err != nil {
+		return nil, err
+	}
+
 	if time.Now().After(transaction.ExpiresAt) {
-		_, err = s.CancelTransaction(ctx, req.TransactionCode)
+		// Release the lock first, cancelTransaction takes it again.
+		tx.Rollback(ctx)
+		_, _, err = s.cancelTransaction(ctx, req.TransactionCode, transactionCancellation{Event: paymentEventExpired})
 		if err != nil {
 			return nil, fmt.Errorf("transaction expired and failed to cancel: %w", err)
 		}
@@ -189,6 +246,23 @@ func (s *TransactionService) ProcessPayment(ctx context.Context, req dto.Process
 	transaction.Status = "paid"
 	transaction.PaidAt = &now
 
+	if err := recordPaymentEvent(ctx, tx, transaction.TransactionID, paymentEventPaid, nil, ""); err != nil {
+		return nil, err
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		Action:     "transaction.paid",
+		EntityType: "transaction",
+		EntityID:   auditEntityID(transaction.TransactionID),
+		Metadata: map[string]any{
+			"transaction_code": transaction.TransactionCode,
+			"total_amount":     transaction.TotalAmount,
+		},
+		Changes: auditDiff(map[string]any{"status": "pending"}, map[string]any{"status": "paid"}),
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	tickets := make([]models.Ticket, 0)
 	rows, err = tx.Query(ctx, `
 		SELECT ticket_id, ticket_code, showtime_id, seat_number, status, transaction_id, created_at
@@ -222,10 +296,86 @@ func (s *TransactionService) ProcessPayment(ctx context.Context, req dto.Process
 	}, nil
 }
 
+// ExpirePendingTransactions marks pending transactions past their payment
+// deadline expired, releasing their seats, and returns how many expired.
+func (s *TransactionService) ExpirePendingTransactions(ctx context.Context) (int, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT transaction_code FROM transactions
+		WHERE status = 'pending' AND expires_at < NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find expired transactions: %w", err)
+	}
+
+	codes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect expired transactions: %w", err)
+	}
+
+	// One transaction failing must not hold back the seats of the others, it
+	// is retried on the next run.
+	expired := 0
+	for _, code := range codes {
+		if _, _, err := s.cancelTransaction(ctx, code, transactionCancellation{Event: paymentEventExpired}); err != nil {
+			log.Printf("Failed to expire transaction %s: %v", code, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// StartExpiryJob runs ExpirePendingTransactions every interval until ctx is
+// cancelled, so unpaid bookings and group bookings give their seats back.
+func (s *TransactionService) StartExpiryJob(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Transaction expiry job stopped")
+				return
+			case <-ticker.C:
+				expired, err := s.ExpirePendingTransactions(ctx)
+				if err != nil {
+					log.Println("Failed to expire transactions:", err)
+				}
+				if expired > 0 {
+					log.Printf("Expired %d pending transactions", expired)
+				}
+			}
+		}
+	}()
+}
+
 func (s *TransactionService) CancelTransaction(ctx context.Context, transactionCode string) (*dto.TransactionResult, error) {
+	result, _, err := s.cancelTransaction(ctx, transactionCode, transactionCancellation{Event: paymentEventCancelled})
+	return result, err
+}
+
+// transactionCancellation describes why a transaction is cancelled. Staff
+// cancellations carry the actor and may also cancel paid transactions whose
+// refund is handled outside the system.
+type transactionCancellation struct {
+	Event     string
+	ActorID   *int
+	Note      string
+	AllowPaid bool
+	// CinemaIDs limits staff to the transactions of their cinemas, nil means
+	// every cinema.
+	CinemaIDs []int
+}
+
+func (s *TransactionService) cancelTransaction(ctx context.Context, transactionCode string, cancel transactionCancellation) (*dto.TransactionResult, int, error) {
 	tx, err := s.db.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
@@ -234,41 +384,57 @@ func (s *TransactionService) CancelTransaction(ctx context.Context, transactionC
 		       recipient_phone_number, total_seats, total_amount, status, 
 		       created_at, expires_at, paid_at, created_by, payment_method_id
 		FROM transactions 
-		WHERE transaction_code = $1`,
+		WHERE transaction_code = $1
+		FOR UPDATE`,
 		transactionCode)
 	if err != nil {
-		return nil, fmt.Errorf("transaction not found: %w", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("transaction not found: %w", err)
 	}
 
 	transaction, err := pgx.CollectOneRow[models.Transaction](rows, pgx.RowToStructByName)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get transaction data: %w", err)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, fmt.Errorf("transaction not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("unable to get transaction data: %w", err)
+	}
+
+	// A transaction without tickets holds no seats, it is still cancelled.
+	var showtimeID, cinemaID int
+	err = tx.QueryRow(ctx, `
+		SELECT tk.showtime_id, s.cinema_id
+		FROM tickets tk
+		JOIN showtimes s ON s.showtime_id = tk.showtime_id
+		WHERE tk.transaction_id = $1 LIMIT 1`,
+		transaction.TransactionID).Scan(&showtimeID, &cinemaID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get showtime: %w", err)
 	}
 
-	if transaction.Status == "paid" {
-		return nil, fmt.Errorf("cannot cancel paid transaction")
+	if !inCinemaScope(cancel.CinemaIDs, cinemaID) {
+		return nil, http.StatusNotFound, fmt.Errorf("transaction not found")
 	}
 
-	if transaction.Status == "cancelled" {
-		return nil, fmt.Errorf("transaction already cancelled")
+	if transaction.Status == "paid" && !cancel.AllowPaid {
+		return nil, http.StatusConflict, fmt.Errorf("cannot cancel paid transaction")
 	}
 
-	var showtimeID int
-	err = tx.QueryRow(ctx, `
-		SELECT showtime_id FROM tickets 
-		WHERE transaction_id = $1 LIMIT 1`,
-		transaction.TransactionID).Scan(&showtimeID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get showtime: %w", err)
+	if transaction.Status == "cancelled" || transaction.Status == "expired" {
+		return nil, http.StatusConflict, fmt.Errorf("transaction already cancelled")
+	}
+
+	// Expiry keeps its own status so reports can tell it from cancellations.
+	status := "cancelled"
+	if cancel.Event == paymentEventExpired {
+		status = "expired"
 	}
 
 	_, err = tx.Exec(ctx, `
 		UPDATE transactions 
-		SET status = 'cancelled' 
+		SET status = $2 
 		WHERE transaction_id = $1`,
-		transaction.TransactionID)
+		transaction.TransactionID, status)
 	if err != nil {
-		return nil, fmt.Errorf("failed to cancel transaction: %w", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to cancel transaction: %w", err)
 	}
 
 	_, err = tx.Exec(ctx, `
@@ -277,19 +443,52 @@ func (s *TransactionService) CancelTransaction(ctx context.Context, transactionC
 		WHERE transaction_id = $1`,
 		transaction.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to cancel tickets: %w", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to cancel tickets: %w", err)
 	}
 
+	if showtimeID != 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE showtimes 
+			SET available_seats = available_seats + $1 
+			WHERE showtime_id = $2`,
+			transaction.TotalSeats, showtimeID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to release seats: %w", err)
+		}
+	}
+
+	// Shares of a group booking go with it, paid ones are refunded outside the
+	// system.
 	_, err = tx.Exec(ctx, `
-		UPDATE showtimes 
-		SET available_seats = available_seats + $1 
-		WHERE showtime_id = $2`,
-		transaction.TotalSeats, showtimeID)
+		UPDATE group_booking_shares
+		SET status = CASE WHEN status = 'paid' THEN 'refund_due' ELSE 'cancelled' END
+		WHERE transaction_id = $1 AND status IN ('pending', 'paid')`,
+		transaction.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to release seats: %w", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to cancel group shares: %w", err)
+	}
+
+	if err := recordPaymentEvent(ctx, tx, transaction.TransactionID, cancel.Event, cancel.ActorID, cancel.Note); err != nil {
+		return nil, http.StatusInternalServerError, err
 	}
 
-	transaction.Status = "cancelled"
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    cancel.ActorID,
+		Action:     "transaction." + cancel.Event,
+		EntityType: "transaction",
+		EntityID:   auditEntityID(transaction.TransactionID),
+		Metadata: map[string]any{
+			"transaction_code": transaction.TransactionCode,
+			"total_amount":     transaction.TotalAmount,
+			"reason":           cancel.Note,
+		},
+		Changes: auditDiff(map[string]any{"status": transaction.Status}, map[string]any{"status": status}),
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	transaction.Status = status
 
 	tickets := make([]models.Ticket, 0)
 	rows, err = tx.Query(ctx, `
@@ -298,7 +497,7 @@ func (s *TransactionService) CancelTransaction(ctx context.Context, transactionC
 		WHERE transaction_id = $1`,
 		transaction.TransactionID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tickets: %w", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get tickets: %w", err)
 	}
 	defer rows.Close()
 
@@ -306,13 +505,13 @@ func (s *TransactionService) CancelTransaction(ctx context.Context, transactionC
 		var ticket models.Ticket
 		if err := rows.Scan(&ticket.TicketID, &ticket.TicketCode, &ticket.ShowtimeID,
 			&ticket.SeatNumber, &ticket.Status, &ticket.TransactionID, &ticket.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+			return nil, http.StatusInternalServerError, fmt.Errorf("failed to scan ticket: %w", err)
 		}
 		tickets = append(tickets, ticket)
 	}
 
 	if err = tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
 	transactionResponse := toTransactionResponse(transaction)
@@ -321,7 +520,7 @@ func (s *TransactionService) CancelTransaction(ctx context.Context, transactionC
 	return &dto.TransactionResult{
 		Transaction: transactionResponse,
 		Tickets:     ticketResponse,
-	}, nil
+	}, http.StatusOK, nil
 }
 
 func (s *TransactionService) GetTransactions(ctx context.Context, transactionCode string) (*[]dto.TransactionListResponse, error) {
@@ -349,8 +548,45 @@ func (s *TransactionService) GetTransactions(ctx context.Context, transactionCod
 		return nil, err
 	}
 
+	results := groupTransactionRows(joinRows)
+	return &results, nil
+
+}
+
+func (s *TransactionService) GetUserTransactions(ctx context.Context, userID int) ([]dto.TransactionListResponse, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT 
+  			t.transaction_id, t.transaction_code, t.status, t.total_amount, t.expires_at, t.created_at,
+  			tk.seat_number,
+  			s.showtime_id, s.show_datetime, s.price,
+  			m.movie_id, m.title AS movie_title,
+  			c.id AS cinema_id, c.name AS cinema_name, c.location AS cinema_location
+		FROM transactions t
+		LEFT JOIN tickets tk ON t.transaction_id = tk.transaction_id
+		LEFT JOIN showtimes s ON tk.showtime_id = s.showtime_id
+		LEFT JOIN movies m ON s.movie_id = m.movie_id
+		LEFT JOIN cinemas c ON s.cinema_id = c.id
+		WHERE t.created_by = $1
+		ORDER BY t.transaction_id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user transactions: %w", err)
+	}
+
+	joinRows, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TransactionJoinRow])
+	if err != nil {
+		return nil, err
+	}
+
+	return groupTransactionRows(joinRows), nil
+}
+
+// groupTransactionRows folds one-row-per-ticket join results into one entry
+// per transaction. Rows must be ordered by transaction.
+func groupTransactionRows(joinRows []models.TransactionJoinRow) []dto.TransactionListResponse {
 	var (
-		results   []dto.TransactionListResponse
+		results   = []dto.TransactionListResponse{}
 		lastTxID  int
 		currentTx *dto.TransactionListResponse
 	)
@@ -365,21 +601,27 @@ func (s *TransactionService) GetTransactions(ctx context.Context, transactionCod
 				TotalAmount:     row.TotalAmount,
 				ExpiresAt:       row.ExpiresAt,
 				CreatedAt:       row.CreatedAt,
-				Movie: dto.MovieResponse{
+				Seats:           []string{},
+			}
+			if row.MovieID != nil {
+				tx.Movie = dto.MovieResponse{
 					MovieID: *row.MovieID,
 					Title:   *row.MovieTitle,
-				},
-				Showtime: dto.ShowtimeResponse{
+				}
+			}
+			if row.ShowtimeID != nil {
+				tx.Showtime = dto.ShowtimeResponse{
 					ShowtimeID:   *row.ShowtimeID,
 					ShowDatetime: *row.ShowDatetime,
 					Price:        *row.Price,
-				},
-				Cinema: dto.CinemaResponse{
+				}
+			}
+			if row.CinemaID != nil {
+				tx.Cinema = dto.CinemaResponse{
 					CinemaID: *row.CinemaID,
 					Name:     *row.CinemaName,
 					Location: *row.CinemaLocation,
-				},
-				Seats: []string{},
+				}
 			}
 			results = append(results, tx)
 			currentTx = &results[len(results)-1]
@@ -390,8 +632,7 @@ func (s *TransactionService) GetTransactions(ctx context.Context, transactionCod
 		}
 	}
 
-	return &results, nil
-
+	return results
 }
 
 func (s *TransactionService) GetTransactionByCode(ctx context.Context, transactionCode string) (*dto.TransactionResult, error) {
@@ -440,6 +681,29 @@ func (s *TransactionService) GetTransactionByCode(ctx context.Context, transacti
 	}, nil
 }
 
+// checkMovieBookable refuses payments for a movie that was deleted while the
+// booking was pending. The share lock waits for a concurrent DeleteMovie, which
+// in turn sees this payment once it commits.
+func checkMovieBookable(ctx context.Context, tx pgx.Tx, transactionID int) error {
+	var deleted bool
+	err := tx.QueryRow(ctx, `
+		SELECT m.deleted_at IS NOT NULL
+		FROM tickets tk
+		JOIN showtimes s ON s.showtime_id = tk.showtime_id
+		JOIN movies m ON m.movie_id = s.movie_id
+		WHERE tk.transaction_id = $1
+		LIMIT 1
+		FOR SHARE OF m`,
+		transactionID).Scan(&deleted)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to check movie: %w", err)
+	}
+	if deleted {
+		return errors.New("movie is no longer available")
+	}
+	return nil
+}
+
 func toTransactionResponse(t models.Transaction) dto.TransactionResponse {
 	return dto.TransactionResponse{
 		TransactionID:     t.TransactionID,
@@ -454,6 +718,7 @@ func toTransactionResponse(t models.Transaction) dto.TransactionResponse {
 		ExpiresAt:         t.ExpiresAt,
 		PaidAt:            t.PaidAt,
 		CreatedBy:         t.CreatedBy,
+		PaymentMethodID:   t.PaymentMethodID,
 	}
 }
 
@@ -473,3 +738,32 @@ func toTicketResponse(tickets []models.Ticket) []dto.TicketResponse {
 
 	return responses
 }
+
+// checkAgeRestriction blocks bookings of restricted titles. A birth date on
+// the buyer's profile is checked against the show date; without one the
+// buyer must acknowledge the age rating.
+func (s *TransactionService) checkAgeRestriction(ctx context.Context, tx pgx.Tx, userID int, ageRating string, showDate time.Time, acknowledged bool) error {
+	minimumAge := utils.MinimumAge(ageRating)
+	if minimumAge == 0 {
+		return nil
+	}
+
+	var birthDate *time.Time
+	err := tx.QueryRow(ctx,
+		"SELECT birth_date FROM profile WHERE user_id = $1", userID).Scan(&birthDate)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	if birthDate != nil {
+		if utils.AgeOn(*birthDate, showDate) < minimumAge {
+			return fmt.Errorf("this movie is rated %s and requires a minimum age of %d", ageRating, minimumAge)
+		}
+		return nil
+	}
+
+	if !acknowledged {
+		return fmt.Errorf("this movie is rated %s, confirm you are at least %d years old with age_acknowledged", ageRating, minimumAge)
+	}
+	return nil
+}
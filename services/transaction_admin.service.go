@@ -0,0 +1,404 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"noir-backend/utils"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var transactionStatuses = map[string]bool{"pending": true, "paid": true, "cancelled": true, "expired": true}
+
+func IsValidTransactionStatus(status string) bool {
+	return transactionStatuses[status]
+}
+
+// SearchTransactions lists transactions for support staff, newest first.
+// Email and phone match partially, the code matches by prefix and the date
+// range applies to when the booking was made.
+func (s *TransactionService) SearchTransactions(ctx context.Context, filter dto.TransactionFilter, limit, offset int) ([]dto.AdminTransactionListItem, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if filter.Email != "" {
+		args = append(args, "%"+strings.ToLower(filter.Email)+"%")
+		conditions = append(conditions, fmt.Sprintf("LOWER(t.recipient_email) LIKE $%d", len(args)))
+	}
+	if filter.Phone != "" {
+		args = append(args, "%"+filter.Phone+"%")
+		conditions = append(conditions, fmt.Sprintf("t.recipient_phone_number LIKE $%d", len(args)))
+	}
+	if filter.CodePrefix != "" {
+		args = append(args, strings.ToUpper(filter.CodePrefix)+"%")
+		conditions = append(conditions, fmt.Sprintf("UPPER(t.transaction_code) LIKE $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", len(args)))
+	}
+	if filter.ShowtimeID != 0 {
+		args = append(args, filter.ShowtimeID)
+		conditions = append(conditions, fmt.Sprintf("ts.showtime_id = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("t.created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("t.created_at < $%d", len(args)))
+	}
+	if filter.CinemaIDs != nil {
+		args = append(args, filter.CinemaIDs)
+		conditions = append(conditions, fmt.Sprintf("s.cinema_id = ANY($%d)", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	from := `
+		FROM transactions t
+		LEFT JOIN LATERAL (
+			SELECT tk.showtime_id FROM tickets tk WHERE tk.transaction_id = t.transaction_id LIMIT 1
+		) ts ON true
+		LEFT JOIN showtimes s ON s.showtime_id = ts.showtime_id
+		LEFT JOIN movies m ON m.movie_id = s.movie_id
+		LEFT JOIN cinemas c ON c.id = s.cinema_id
+		LEFT JOIN payment_method pm ON pm.payment_method_id = t.payment_method_id`
+
+	var total int
+	err := s.db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) %s %s", from, where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count transactions: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`
+		SELECT t.transaction_id, t.transaction_code, t.recipient_email, t.recipient_full_name,
+		       t.recipient_phone_number, t.status, t.total_seats, t.total_amount,
+		       t.created_at, t.expires_at, t.paid_at,
+		       s.showtime_id, s.show_datetime, m.title, c.name, pm.name
+		%s
+		%s
+		ORDER BY t.created_at DESC, t.transaction_id DESC
+		LIMIT $%d OFFSET $%d`, from, where, len(args)+1, len(args)+2),
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search transactions: %w", err)
+	}
+
+	transactions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.AdminTransactionListItem, error) {
+		var t dto.AdminTransactionListItem
+		err := row.Scan(&t.TransactionID, &t.TransactionCode, &t.RecipientEmail, &t.RecipientFullName,
+			&t.RecipientPhone, &t.Status, &t.TotalSeats, &t.TotalAmount,
+			&t.CreatedAt, &t.ExpiresAt, &t.PaidAt,
+			&t.ShowtimeID, &t.ShowDatetime, &t.MovieTitle, &t.CinemaName, &t.PaymentMethod)
+		return t, err
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect transactions: %w", err)
+	}
+
+	return transactions, total, nil
+}
+
+// GetTransactionDetail returns a transaction with its showtime, tickets and
+// payment history. Transactions outside cinemaIDs are reported as not found.
+func (s *TransactionService) GetTransactionDetail(ctx context.Context, transactionCode string, cinemaIDs []int) (*dto.TransactionDetailResponse, int, error) {
+	var detail dto.TransactionDetailResponse
+	var paymentMethodID *int
+	var paymentMethodName, paymentMethodCode *string
+	t := &detail.Transaction
+	err := s.db.QueryRow(ctx, `
+		SELECT t.transaction_id, t.transaction_code, t.recipient_email, t.recipient_full_name,
+		       t.recipient_phone_number, t.total_seats, t.total_amount, t.status,
+		       t.created_at, t.expires_at, t.paid_at, t.created_by,
+		       u.email,
+		       pm.payment_method_id, pm.name, pm.code,
+		       s.showtime_id, s.show_datetime, s.price,
+		       m.movie_id, m.title,
+		       c.id, c.name, c.location
+		FROM transactions t
+		LEFT JOIN users u ON u.user_id = t.created_by
+		LEFT JOIN payment_method pm ON pm.payment_method_id = t.payment_method_id
+		JOIN LATERAL (
+			SELECT tk.showtime_id FROM tickets tk WHERE tk.transaction_id = t.transaction_id LIMIT 1
+		) ts ON true
+		JOIN showtimes s ON s.showtime_id = ts.showtime_id
+		JOIN movies m ON m.movie_id = s.movie_id
+		JOIN cinemas c ON c.id = s.cinema_id
+		WHERE t.transaction_code = $1`,
+		transactionCode).Scan(
+		&t.TransactionID, &t.TransactionCode, &t.RecipientEmail, &t.RecipientFullName,
+		&t.RecipientPhone, &t.TotalSeats, &t.TotalAmount, &t.Status,
+		&t.CreatedAt, &t.ExpiresAt, &t.PaidAt, &t.CreatedBy,
+		&detail.BuyerEmail,
+		&paymentMethodID, &paymentMethodName, &paymentMethodCode,
+		&detail.Showtime.ShowtimeID, &detail.Showtime.ShowDatetime, &detail.Showtime.Price,
+		&detail.Movie.MovieID, &detail.Movie.Title,
+		&detail.Cinema.CinemaID, &detail.Cinema.Name, &detail.Cinema.Location)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("transaction not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if !inCinemaScope(cinemaIDs, detail.Cinema.CinemaID) {
+		return nil, http.StatusNotFound, errors.New("transaction not found")
+	}
+	if paymentMethodID != nil {
+		t.PaymentMethodID = *paymentMethodID
+		detail.PaymentMethod = &dto.PaymentMethodResponse{
+			PaymentMethodID: *paymentMethodID,
+			Name:            *paymentMethodName,
+			Code:            *paymentMethodCode,
+		}
+	}
+
+	rows, err := s.db.Query(ctx, `
+		SELECT ticket_id, ticket_code, showtime_id, seat_number, status, transaction_id, created_at
+		FROM tickets
+		WHERE transaction_id = $1
+		ORDER BY seat_number`,
+		t.TransactionID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get tickets: %w", err)
+	}
+	tickets, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Ticket])
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to collect tickets: %w", err)
+	}
+	detail.Tickets = toTicketResponse(tickets)
+
+	rows, err = s.db.Query(ctx, `
+		SELECT pe.id, pe.event, pe.actor_id, u.email, pe.note, pe.created_at
+		FROM payment_events pe
+		LEFT JOIN users u ON u.user_id = pe.actor_id
+		WHERE pe.transaction_id = $1
+		ORDER BY pe.created_at, pe.id`,
+		t.TransactionID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get payment events: %w", err)
+	}
+	detail.Events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.PaymentEventResponse, error) {
+		var e dto.PaymentEventResponse
+		err := row.Scan(&e.ID, &e.Event, &e.ActorID, &e.ActorEmail, &e.Note, &e.CreatedAt)
+		return e, err
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to collect payment events: %w", err)
+	}
+
+	return &detail, http.StatusOK, nil
+}
+
+// CancelTransactionByStaff cancels a pending or paid transaction and releases
+// its seats. Refunds of paid transactions are handled outside the system.
+func (s *TransactionService) CancelTransactionByStaff(ctx context.Context, actorID int, transactionCode, reason string, cinemaIDs []int) (*dto.TransactionResult, int, error) {
+	return s.cancelTransaction(ctx, transactionCode, transactionCancellation{
+		Event:     paymentEventCancelled,
+		ActorID:   &actorID,
+		Note:      reason,
+		AllowPaid: true,
+		CinemaIDs: cinemaIDs,
+	})
+}
+
+// MarkTransactionPaid records a payment received outside the payment flow,
+// e.g. a bank transfer confirmed by hand. The transaction must still be
+// pending, an expired one only qualifies until the expiry job releases it.
+func (s *TransactionService) MarkTransactionPaid(ctx context.Context, actorID int, transactionCode, reason string, cinemaIDs []int) (*dto.TransactionResponse, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT transaction_id, transaction_code, recipient_email, recipient_full_name,
+		       recipient_phone_number, total_seats, total_amount, status,
+		       created_at, expires_at, paid_at, created_by, payment_method_id
+		FROM transactions
+		WHERE transaction_code = $1
+		FOR UPDATE`,
+		transactionCode)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	transaction, err := pgx.CollectOneRow(rows, pgx.RowToStructByName[models.Transaction])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("transaction not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	if status, err := checkTransactionScope(ctx, tx, transaction.TransactionID, cinemaIDs); err != nil {
+		return nil, status, err
+	}
+	if err := checkMovieBookable(ctx, tx, transaction.TransactionID); err != nil {
+		return nil, http.StatusConflict, err
+	}
+	if transaction.Status != "pending" {
+		return nil, http.StatusConflict, fmt.Errorf("transaction is %s, only pending transactions can be marked paid", transaction.Status)
+	}
+
+	now := time.Now()
+	_, err = tx.Exec(ctx,
+		"UPDATE transactions SET status = 'paid', paid_at = $2 WHERE transaction_id = $1",
+		transaction.TransactionID, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update transaction: %w", err)
+	}
+
+	// A payment confirmed by staff settles whatever group shares were still
+	// outstanding.
+	_, err = tx.Exec(ctx,
+		"UPDATE group_booking_shares SET status = 'paid', paid_at = $2 WHERE transaction_id = $1 AND status = 'pending'",
+		transaction.TransactionID, now)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to update group shares: %w", err)
+	}
+
+	if err := recordPaymentEvent(ctx, tx, transaction.TransactionID, paymentEventPaid, &actorID, reason); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "transaction.marked_paid",
+		EntityType: "transaction",
+		EntityID:   auditEntityID(transaction.TransactionID),
+		Metadata: map[string]any{
+			"transaction_code": transaction.TransactionCode,
+			"total_amount":     transaction.TotalAmount,
+			"reason":           reason,
+		},
+		Changes: auditDiff(map[string]any{"status": transaction.Status}, map[string]any{"status": "paid"}),
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	transaction.Status = "paid"
+	transaction.PaidAt = &now
+	response := toTransactionResponse(transaction)
+	return &response, http.StatusOK, nil
+}
+
+// ResendConfirmation emails the booking confirmation of a paid transaction to
+// its recipient again.
+func (s *TransactionService) ResendConfirmation(ctx context.Context, actorID int, transactionCode string, cinemaIDs []int) (int, error) {
+	detail, status, err := s.GetTransactionDetail(ctx, transactionCode, cinemaIDs)
+	if err != nil {
+		return status, err
+	}
+	if detail.Transaction.Status != "paid" {
+		return http.StatusConflict, errors.New("only paid transactions have a confirmation")
+	}
+
+	if err := sendBookingConfirmation(detail); err != nil {
+		return http.StatusBadGateway, fmt.Errorf("failed to send confirmation: %w", err)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	transactionID := detail.Transaction.TransactionID
+	if err := recordPaymentEvent(ctx, tx, transactionID, paymentEventConfirmationSent, &actorID, ""); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "transaction.confirmation_resent",
+		EntityType: "transaction",
+		EntityID:   auditEntityID(transactionID),
+		Metadata: map[string]any{
+			"transaction_code": detail.Transaction.TransactionCode,
+			"recipient_email":  detail.Transaction.RecipientEmail,
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	return http.StatusOK, nil
+}
+
+func checkTransactionScope(ctx context.Context, tx pgx.Tx, transactionID int, cinemaIDs []int) (int, error) {
+	if cinemaIDs == nil {
+		return http.StatusOK, nil
+	}
+
+	var cinemaID int
+	err := tx.QueryRow(ctx, `
+		SELECT s.cinema_id
+		FROM tickets tk
+		JOIN showtimes s ON s.showtime_id = tk.showtime_id
+		WHERE tk.transaction_id = $1 LIMIT 1`,
+		transactionID).Scan(&cinemaID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get showtime: %w", err)
+	}
+	if !inCinemaScope(cinemaIDs, cinemaID) {
+		return http.StatusNotFound, errors.New("transaction not found")
+	}
+	return http.StatusOK, nil
+}
+
+func sendBookingConfirmation(detail *dto.TransactionDetailResponse) error {
+	seats := []string{}
+	ticketCodes := []string{}
+	for _, ticket := range detail.Tickets {
+		if ticket.Status == "cancelled" {
+			continue
+		}
+		seats = append(seats, ticket.SeatNumber)
+		ticketCodes = append(ticketCodes, fmt.Sprintf("%s (seat %s)", ticket.TicketCode, ticket.SeatNumber))
+	}
+
+	data := struct {
+		Name            string
+		TransactionCode string
+		MovieTitle      string
+		CinemaName      string
+		CinemaLocation  string
+		Showtime        string
+		Seats           string
+		TotalAmount     string
+		Tickets         []string
+	}{
+		Name:            detail.Transaction.RecipientFullName,
+		TransactionCode: detail.Transaction.TransactionCode,
+		MovieTitle:      detail.Movie.Title,
+		CinemaName:      detail.Cinema.Name,
+		CinemaLocation:  detail.Cinema.Location,
+		Showtime:        detail.Showtime.ShowDatetime.Format("Monday, 2 January 2006 15:04"),
+		Seats:           strings.Join(seats, ", "),
+		TotalAmount:     fmt.Sprintf("%.2f", detail.Transaction.TotalAmount),
+		Tickets:         ticketCodes,
+	}
+
+	body, err := utils.RenderMailTemplate("booking_confirmation_email.txt", data)
+	if err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("Your booking %s for %s", detail.Transaction.TransactionCode, detail.Movie.Title)
+	return utils.SendMail(detail.Transaction.RecipientEmail, subject, body)
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"noir-backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	twoFactorChallengeTTL  = 5 * time.Minute
+	twoFactorMaxAttempts   = 5
+	twoFactorRecoveryCodes = 10
+)
+
+func (s *AuthService) createTwoFactorChallenge(ctx context.Context, userID int) (string, error) {
+	challenge := uuid.New().String()
+	err := s.redis.Set(ctx, fmt.Sprintf("2fa-challenge:%s", challenge), userID, twoFactorChallengeTTL).Err()
+	if err != nil {
+		return "", fmt.Errorf("failed to create two-factor challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// VerifyTwoFactorLogin completes a login started by Login when the account has
+// two-factor authentication enabled, using either a TOTP or a recovery code.
+func (s *AuthService) VerifyTwoFactorLogin(ctx context.Context, req dto.TwoFactorLoginRequest) (*dto.AuthResponse, error) {
+	if req.Code == "" && req.RecoveryCode == "" {
+		return nil, errors.New("code or recovery_code is required")
+	}
+
+	challengeKey := fmt.Sprintf("2fa-challenge:%s", req.ChallengeToken)
+	userID, err := s.redis.Get(ctx, challengeKey).Int()
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge token")
+	}
+
+	attemptsKey := fmt.Sprintf("2fa-attempts:%s", req.ChallengeToken)
+	attempts, err := s.redis.Incr(ctx, attemptsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify code: %w", err)
+	}
+	s.redis.Expire(ctx, attemptsKey, twoFactorChallengeTTL)
+	if attempts > twoFactorMaxAttempts {
+		s.redis.Del(ctx, challengeKey)
+		return nil, errors.New("too many attempts, please login again")
+	}
+
+	user := &models.User{}
+	err = s.db.QueryRow(ctx,
+		`SELECT user_id, email, role, is_active, totp_secret, totp_enabled, created_at, updated_at, last_login
+		FROM users WHERE user_id = $1`,
+		userID).Scan(&user.UserID, &user.Email, &user.Role, &user.IsActive, &user.TOTPSecret, &user.TOTPEnabled, &user.CreatedAt, &user.UpdatedAt, &user.LastLogin)
+	if err != nil {
+		return nil, errors.New("invalid or expired challenge token")
+	}
+
+	if !user.IsActive {
+		return nil, errors.New("account disabled")
+	}
+
+	if req.Code != "" {
+		if !user.TOTPEnabled || user.TOTPSecret == nil {
+			return nil, errors.New("invalid two-factor code")
+		}
+		accepted, err := acceptTOTP(ctx, s.db, user.UserID, *user.TOTPSecret, req.Code)
+		if err != nil {
+			return nil, err
+		}
+		if !accepted {
+			return nil, errors.New("invalid two-factor code")
+		}
+	} else {
+		result, err := s.db.Exec(ctx, `
+			UPDATE user_recovery_codes SET used_at = NOW()
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+			user.UserID, utils.HashRecoveryCode(req.RecoveryCode))
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify recovery code: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return nil, errors.New("invalid recovery code")
+		}
+	}
+
+	s.redis.Del(ctx, challengeKey, attemptsKey)
+
+	return s.completeLogin(ctx, user, true)
+}
+
+// EnrollTwoFactor generates a new pending secret. It only takes effect once
+// confirmed with ConfirmTwoFactor.
+func (s *AuthService) EnrollTwoFactor(ctx context.Context, userID int) (*dto.TwoFactorEnrollResponse, int, error) {
+	var email string
+	var enabled bool
+	err := s.db.QueryRow(ctx,
+		"SELECT email, totp_enabled FROM users WHERE user_id = $1", userID).Scan(&email, &enabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, http.StatusNotFound, errors.New("user not found")
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if enabled {
+		return nil, http.StatusConflict, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	_, err = s.db.Exec(ctx,
+		"UPDATE users SET totp_secret = $1, totp_last_step = NULL, updated_at = NOW() WHERE user_id = $2", secret, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to save secret: %w", err)
+	}
+
+	return &dto.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OtpauthURI: utils.TOTPURI(utils.Load().TwoFactor.Issuer, email, secret),
+	}, http.StatusOK, nil
+}
+
+func (s *AuthService) ConfirmTwoFactor(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var secret *string
+	var enabled bool
+	err = tx.QueryRow(ctx,
+		"SELECT totp_secret, totp_enabled FROM users WHERE user_id = $1 FOR UPDATE", userID).Scan(&secret, &enabled)
+	if err != nil {
+		return nil, http.StatusNotFound, errors.New("user not found")
+	}
+
+	if enabled {
+		return nil, http.StatusConflict, errors.New("two-factor authentication is already enabled")
+	}
+	if secret == nil {
+		return nil, http.StatusBadRequest, errors.New("two-factor enrollment has not been started")
+	}
+	accepted, err := acceptTOTP(ctx, tx, userID, *secret, code)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !accepted {
+		return nil, http.StatusBadRequest, errors.New("invalid two-factor code")
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE users SET totp_enabled = true, updated_at = NOW() WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+
+	codes, err := replaceRecoveryCodes(ctx, tx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &userID,
+		Action:     "user.2fa_enabled",
+		EntityType: "user",
+		EntityID:   auditEntityID(userID),
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, http.StatusOK, nil
+}
+
+func (s *AuthService) DisableTwoFactor(ctx context.Context, userID int, code string) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var secret *string
+	var enabled bool
+	var role string
+	err = tx.QueryRow(ctx,
+		"SELECT totp_secret, totp_enabled, role FROM users WHERE user_id = $1 FOR UPDATE", userID).Scan(&secret, &enabled, &role)
+	if err != nil {
+		return http.StatusNotFound, errors.New("user not found")
+	}
+
+	if !enabled || secret == nil {
+		return http.StatusBadRequest, errors.New("two-factor authentication is not enabled")
+	}
+	if role == utils.RoleAdmin && utils.Load().TwoFactor.RequiredForAdmin {
+		return http.StatusForbidden, errors.New("two-factor authentication is mandatory for admin")
+	}
+	accepted, err := acceptTOTP(ctx, tx, userID, *secret, code)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if !accepted {
+		return http.StatusBadRequest, errors.New("invalid two-factor code")
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE users SET totp_enabled = false, totp_secret = NULL, totp_last_step = NULL, updated_at = NOW() WHERE user_id = $1", userID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to disable two-factor authentication: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &userID,
+		Action:     "user.2fa_disabled",
+		EntityType: "user",
+		EntityID:   auditEntityID(userID),
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	return http.StatusOK, nil
+}
+
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID int, code string) (*dto.RecoveryCodesResponse, int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var secret *string
+	var enabled bool
+	err = tx.QueryRow(ctx,
+		"SELECT totp_secret, totp_enabled FROM users WHERE user_id = $1 FOR UPDATE", userID).Scan(&secret, &enabled)
+	if err != nil {
+		return nil, http.StatusNotFound, errors.New("user not found")
+	}
+
+	if !enabled || secret == nil {
+		return nil, http.StatusBadRequest, errors.New("two-factor authentication is not enabled")
+	}
+	accepted, err := acceptTOTP(ctx, tx, userID, *secret, code)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if !accepted {
+		return nil, http.StatusBadRequest, errors.New("invalid two-factor code")
+	}
+
+	codes, err := replaceRecoveryCodes(ctx, tx, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &userID,
+		Action:     "user.2fa_recovery_codes_regenerated",
+		EntityType: "user",
+		EntityID:   auditEntityID(userID),
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, http.StatusOK, nil
+}
+
+// acceptTOTP validates code and consumes its time step, so a code observed by
+// someone else cannot be replayed within its validity window. The conditional
+// update also settles two logins racing with the same code.
+func acceptTOTP(ctx context.Context, db execer, userID int, secret, code string) (bool, error) {
+	step, ok := utils.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+
+	result, err := db.Exec(ctx, `
+		UPDATE users SET totp_last_step = $2
+		WHERE user_id = $1 AND (totp_last_step IS NULL OR totp_last_step < $2)`,
+		userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify code: %w", err)
+	}
+	return result.RowsAffected() == 1, nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx pgx.Tx, userID int) ([]string, error) {
+	codes, err := utils.GenerateRecoveryCodes(twoFactorRecoveryCodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM user_recovery_codes WHERE user_id = $1", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, code := range codes {
+		_, err = tx.Exec(ctx,
+			"INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)",
+			userID, utils.HashRecoveryCode(code))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+		}
+	}
+
+	return codes, nil
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/models"
+	"noir-backend/utils"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+type UserService struct {
+	db    *pgxpool.Pool
+	redis *redis.Client
+}
+
+func NewUserService(db *pgxpool.Pool, redis *redis.Client) *UserService {
+	return &UserService{db: db, redis: redis}
+}
+
+func (s *UserService) ListUsers(ctx context.Context, filter dto.UserFilter, limit, offset int) ([]dto.UserListItem, int, error) {
+	conditions := []string{}
+	args := []any{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf(
+			"(u.email ILIKE $%d OR p.first_name ILIKE $%d OR p.last_name ILIKE $%d OR p.phone_number ILIKE $%d)",
+			len(args), len(args), len(args), len(args)))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		conditions = append(conditions, fmt.Sprintf("u.role = $%d", len(args)))
+	}
+	switch filter.Status {
+	case "active":
+		conditions = append(conditions, "u.is_active = true")
+	case "disabled":
+		conditions = append(conditions, "u.is_active = false")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err := s.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM users u
+		LEFT JOIN profile p ON p.user_id = u.user_id
+		%s`, where), args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`
+		SELECT u.user_id, u.email, u.role, u.is_active, p.first_name, p.last_name,
+		       p.phone_number, u.created_at, u.last_login
+		FROM users u
+		LEFT JOIN profile p ON p.user_id = u.user_id
+		%s
+		ORDER BY u.created_at DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2),
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	users, err := pgx.CollectRows(rows, scanUserListItem)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to collect users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, userID int) (*dto.UserListItem, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT u.user_id, u.email, u.role, u.is_active, p.first_name, p.last_name,
+		       p.phone_number, u.created_at, u.last_login
+		FROM users u
+		LEFT JOIN profile p ON p.user_id = u.user_id
+		WHERE u.user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user, err := pgx.CollectOneRow(rows, scanUserListItem)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("user not found")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (s *UserService) GetUserCinemaIDs(ctx context.Context, userID int) ([]int, error) {
+	rows, err := s.db.Query(ctx,
+		"SELECT cinema_id FROM cinema_managers WHERE user_id = $1 ORDER BY cinema_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get managed cinemas: %w", err)
+	}
+
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+func (s *UserService) UpdateRole(ctx context.Context, actorID, userID int, req dto.UpdateRoleRequest) (int, error) {
+	if !utils.IsValidRole(req.Role) {
+		return http.StatusBadRequest, fmt.Errorf("invalid role: %s", req.Role)
+	}
+	if req.Role != utils.RoleCinemaManager && len(req.CinemaIDs) > 0 {
+		return http.StatusBadRequest, fmt.Errorf("cinema_ids can only be assigned to cinema managers")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var previousRole string
+	err = tx.QueryRow(ctx,
+		"SELECT role FROM users WHERE user_id = $1 FOR UPDATE", userID).Scan(&previousRole)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, fmt.Errorf("user not found")
+	} else if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if userID == actorID && req.Role != previousRole {
+		return http.StatusBadRequest, fmt.Errorf("you cannot change your own role")
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE users SET role = $1, updated_at = NOW() WHERE user_id = $2", req.Role, userID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	var previousCinemaIDs []int
+	err = tx.QueryRow(ctx,
+		"SELECT COALESCE(ARRAY_AGG(cinema_id ORDER BY cinema_id), '{}') FROM cinema_managers WHERE user_id = $1",
+		userID).Scan(&previousCinemaIDs)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get managed cinemas: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM cinema_managers WHERE user_id = $1", userID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to update managed cinemas: %w", err)
+	}
+
+	for _, cinemaID := range req.CinemaIDs {
+		_, err = tx.Exec(ctx,
+			"INSERT INTO cinema_managers (user_id, cinema_id) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			userID, cinemaID)
+		if err != nil {
+			return http.StatusBadRequest, fmt.Errorf("failed to assign cinema %d: %w", cinemaID, err)
+		}
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "user.role_changed",
+		EntityType: "user",
+		EntityID:   auditEntityID(userID),
+		Metadata: map[string]any{
+			"previous_role": previousRole,
+			"role":          req.Role,
+			"cinema_ids":    req.CinemaIDs,
+		},
+		Changes: auditDiff(
+			map[string]any{"role": previousRole, "cinema_ids": previousCinemaIDs},
+			map[string]any{"role": req.Role, "cinema_ids": sortedUniqueIDs(req.CinemaIDs)},
+		),
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	// Tokens carry the role claim, so sessions issued with the old role must go.
+	if err := s.revokeSessions(ctx, userID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return http.StatusOK, nil
+}
+
+func (s *UserService) SetActive(ctx context.Context, actorID, userID int, isActive bool) (int, error) {
+	if userID == actorID && !isActive {
+		return http.StatusBadRequest, fmt.Errorf("you cannot disable your own account")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		"UPDATE users SET is_active = $1, updated_at = NOW() WHERE user_id = $2", isActive, userID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to update user status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return http.StatusNotFound, fmt.Errorf("user not found")
+	}
+
+	action := "user.enabled"
+	if !isActive {
+		action = "user.disabled"
+	}
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     action,
+		EntityType: "user",
+		EntityID:   auditEntityID(userID),
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	// The flag only has to outlive the tokens issued before the account was
+	// disabled, login itself checks is_active.
+	key := fmt.Sprintf("disabled-user:%d", userID)
+	if isActive {
+		err = s.redis.Del(ctx, key).Err()
+	} else {
+		err = s.redis.Set(ctx, key, "1", utils.TokenTTL).Err()
+	}
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to update user session state: %w", err)
+	}
+
+	return http.StatusOK, nil
+}
+
+func (s *UserService) ForceLogout(ctx context.Context, actorID, userID int) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("database transaction error")
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM users WHERE user_id = $1)", userID).Scan(&exists)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return http.StatusNotFound, fmt.Errorf("user not found")
+	}
+
+	err = recordAudit(ctx, tx, models.AuditLog{
+		ActorID:    &actorID,
+		Action:     "user.force_logout",
+		EntityType: "user",
+		EntityID:   auditEntityID(userID),
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	// Sessions are only revoked once the audit entry is written, and the entry
+	// is dropped again when revoking fails.
+	if err := s.revokeSessions(ctx, userID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to commit transaction")
+	}
+
+	return http.StatusOK, nil
+}
+
+// revokeSessions invalidates every token issued to the user up to now.
+// AuthMiddleware rejects tokens whose iat is not after the stored timestamp,
+// kept in milliseconds.
+func (s *UserService) revokeSessions(ctx context.Context, userID int) error {
+	err := s.redis.Set(ctx,
+		fmt.Sprintf("revoked-before:%d", userID),
+		time.Now().UnixMilli(),
+		utils.TokenTTL).Err()
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	return nil
+}
+
+func scanUserListItem(row pgx.CollectableRow) (dto.UserListItem, error) {
+	var u dto.UserListItem
+	err := row.Scan(&u.UserID, &u.Email, &u.Role, &u.IsActive, &u.FirstName, &u.LastName,
+		&u.PhoneNumber, &u.CreatedAt, &u.LastLogin)
+	return u, err
+}
+
+func auditEntityID(id int) *string {
+	s := strconv.Itoa(id)
+	return &s
+}
+
+func sortedUniqueIDs(ids []int) []int {
+	unique := []int{}
+	seen := map[int]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sort.Ints(unique)
+	return unique
+}
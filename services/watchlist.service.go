@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"noir-backend/dto"
+	"noir-backend/utils"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const watchlistReminderBatch = 100
+
+type WatchlistService struct {
+	db *pgxpool.Pool
+}
+
+func NewWatchlistService(db *pgxpool.Pool) *WatchlistService {
+	return &WatchlistService{db: db}
+}
+
+func (s *WatchlistService) GetWatchlist(ctx context.Context, userID int) ([]dto.WatchlistItem, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT m.movie_id, m.title, m.poster_path, m.release_date,
+		       (SELECT MIN(s.show_datetime) FROM showtimes s
+		        WHERE s.movie_id = m.movie_id AND s.show_datetime > NOW()),
+		       w.created_at
+		FROM watchlist w
+		JOIN movies m ON m.movie_id = w.movie_id
+		WHERE w.user_id = $1 AND m.deleted_at IS NULL
+		ORDER BY m.release_date ASC`,
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (dto.WatchlistItem, error) {
+		var item dto.WatchlistItem
+		err := row.Scan(&item.MovieID, &item.Title, &item.PosterPath, &item.ReleaseDate, &item.FirstShowtime, &item.AddedAt)
+		return item, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect watchlist: %w", err)
+	}
+
+	return items, nil
+}
+
+// AddToWatchlist saves a movie for the user. Reminders that would already be
+// stale, because the movie is released or on sale, are marked as sent.
+func (s *WatchlistService) AddToWatchlist(ctx context.Context, userID, movieID int) (int, error) {
+	result, err := s.db.Exec(ctx, `
+		INSERT INTO watchlist (user_id, movie_id, on_sale_notified_at, release_notified_at)
+		SELECT $1, m.movie_id,
+		       CASE WHEN EXISTS (
+		           SELECT 1 FROM showtimes s WHERE s.movie_id = m.movie_id AND s.show_datetime > NOW()
+		       ) THEN NOW() END,
+		       CASE WHEN m.release_date <= CURRENT_DATE THEN NOW() END
+		FROM movies m
+		WHERE m.movie_id = $2 AND m.deleted_at IS NULL
+		ON CONFLICT (user_id, movie_id) DO NOTHING`,
+		userID, movieID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to add to watchlist: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		var exists bool
+		err = s.db.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM movies WHERE movie_id = $1 AND deleted_at IS NULL)", movieID).Scan(&exists)
+		if err != nil {
+			return http.StatusInternalServerError, fmt.Errorf("failed to find movie: %w", err)
+		}
+		if !exists {
+			return http.StatusNotFound, errors.New("movie not found")
+		}
+		return http.StatusOK, nil
+	}
+
+	return http.StatusCreated, nil
+}
+
+func (s *WatchlistService) RemoveFromWatchlist(ctx context.Context, userID, movieID int) (int, error) {
+	result, err := s.db.Exec(ctx,
+		"DELETE FROM watchlist WHERE user_id = $1 AND movie_id = $2", userID, movieID)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to remove from watchlist: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return http.StatusNotFound, errors.New("movie is not on the watchlist")
+	}
+	return http.StatusOK, nil
+}
+
+// StartReminderJob periodically emails watchers of movies whose first
+// showtime went on sale or whose release date has arrived. A non-positive
+// interval disables the job.
+func (s *WatchlistService) StartReminderJob(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Println("Watchlist reminder job stopped")
+				return
+			case <-ticker.C:
+				sent, err := s.SendDueReminders(ctx)
+				if err != nil {
+					log.Println("Failed to send watchlist reminders:", err)
+				} else if sent > 0 {
+					log.Printf("Sent %d watchlist reminders", sent)
+				}
+			}
+		}
+	}()
+}
+
+type watchlistReminder struct {
+	id            int
+	email         string
+	name          string
+	movieID       int
+	title         string
+	firstShowtime *time.Time
+}
+
+func (s *WatchlistService) SendDueReminders(ctx context.Context) (int, error) {
+	onSale, err := s.sendReminders(ctx, "on_sale_notified_at", `
+		EXISTS (SELECT 1 FROM showtimes s WHERE s.movie_id = m.movie_id AND s.show_datetime > NOW())`,
+		"watchlist_on_sale_email.txt", "Tickets are on sale for %s")
+	if err != nil {
+		return onSale, err
+	}
+
+	released, err := s.sendReminders(ctx, "release_notified_at",
+		"m.release_date <= CURRENT_DATE",
+		"watchlist_release_email.txt", "%s is out now")
+	return onSale + released, err
+}
+
+// sendReminders claims due watchlist rows by setting notifiedColumn before
+// sending, so several instances never email the same watcher twice. Rows
+// whose email fails are released again for the next run.
+func (s *WatchlistService) sendReminders(ctx context.Context, notifiedColumn, dueCondition, templateName, subject string) (int, error) {
+	rows, err := s.db.Query(ctx, fmt.Sprintf(`
+		WITH due AS (
+			SELECT w.id
+			FROM watchlist w
+			JOIN movies m ON m.movie_id = w.movie_id
+			JOIN users u ON u.user_id = w.user_id
+			WHERE w.%[1]s IS NULL AND m.deleted_at IS NULL AND u.is_active AND %[2]s
+			ORDER BY w.id
+			LIMIT %[3]d
+			FOR UPDATE OF w SKIP LOCKED
+		)
+		UPDATE watchlist w SET %[1]s = NOW()
+		FROM due, users u, movies m
+		WHERE w.id = due.id
+		  AND u.user_id = w.user_id
+		  AND m.movie_id = w.movie_id
+		RETURNING w.id, u.email,
+		          COALESCE((SELECT p.first_name FROM profile p WHERE p.user_id = w.user_id), ''),
+		          m.movie_id, m.title,
+		          (SELECT MIN(s.show_datetime) FROM showtimes s
+		           WHERE s.movie_id = m.movie_id AND s.show_datetime > NOW())`,
+		notifiedColumn, dueCondition, watchlistReminderBatch))
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim reminders: %w", err)
+	}
+
+	reminders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (watchlistReminder, error) {
+		var r watchlistReminder
+		err := row.Scan(&r.id, &r.email, &r.name, &r.movieID, &r.title, &r.firstShowtime)
+		return r, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to collect reminders: %w", err)
+	}
+
+	sent := 0
+	for _, r := range reminders {
+		if err := sendWatchlistReminder(r, templateName, fmt.Sprintf(subject, r.title)); err != nil {
+			log.Printf("Failed to send watchlist reminder %d: %v", r.id, err)
+			_, err = s.db.Exec(ctx,
+				fmt.Sprintf("UPDATE watchlist SET %s = NULL WHERE id = $1", notifiedColumn), r.id)
+			if err != nil {
+				log.Printf("Failed to release watchlist reminder %d: %v", r.id, err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func sendWatchlistReminder(r watchlistReminder, templateName, subject string) error {
+	name := r.name
+	if name == "" {
+		name = "there"
+	}
+
+	data := struct {
+		Name          string
+		Title         string
+		FirstShowtime string
+		MovieURL      string
+	}{
+		Name:     name,
+		Title:    r.title,
+		MovieURL: fmt.Sprintf("%s/movies/%d", utils.Load().AppURL, r.movieID),
+	}
+	if r.firstShowtime != nil {
+		data.FirstShowtime = r.firstShowtime.Format("Monday, 2 January 2006 15:04")
+	}
+
+	body, err := utils.RenderMailTemplate(templateName, data)
+	if err != nil {
+		return err
+	}
+	return utils.SendMail(r.email, subject, body)
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	AgeRatingSU = "SU"
+	AgeRating13 = "13+"
+	AgeRating17 = "17+"
+	AgeRating21 = "21+"
+)
+
+var ageRatingMinimumAge = map[string]int{
+	AgeRatingSU: 0,
+	AgeRating13: 13,
+	AgeRating17: 17,
+	AgeRating21: 21,
+}
+
+// ContentAdvisories lists the advisories a movie can be tagged with.
+var ContentAdvisories = []string{
+	"violence",
+	"language",
+	"sexual_content",
+	"nudity",
+	"drugs",
+	"horror",
+	"self_harm",
+	"smoking",
+}
+
+func IsValidAgeRating(rating string) bool {
+	_, ok := ageRatingMinimumAge[rating]
+	return ok
+}
+
+// MinimumAge returns the age a viewer must have reached to watch a movie with
+// the given rating.
+func MinimumAge(rating string) int {
+	return ageRatingMinimumAge[rating]
+}
+
+func IsValidContentAdvisory(advisory string) bool {
+	return slices.Contains(ContentAdvisories, advisory)
+}
+
+// AgeOn returns the age in whole years of someone born on birthDate at day.
+func AgeOn(birthDate, day time.Time) int {
+	age := day.Year() - birthDate.Year()
+	if day.Month() < birthDate.Month() || (day.Month() == birthDate.Month() && day.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
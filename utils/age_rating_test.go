@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAgeOn(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatalf("parse %s: %v", s, err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name      string
+		birthDate string
+		day       string
+		want      int
+	}{
+		{name: "birthday today", birthDate: "2008-06-15", day: "2025-06-15", want: 17},
+		{name: "day before birthday", birthDate: "2008-06-15", day: "2025-06-14", want: 16},
+		{name: "earlier month", birthDate: "2008-06-15", day: "2025-05-30", want: 16},
+		{name: "later month", birthDate: "2008-06-15", day: "2025-07-01", want: 17},
+		{name: "leap day birthday in common year", birthDate: "2004-02-29", day: "2025-02-28", want: 20},
+		{name: "leap day birthday passed", birthDate: "2004-02-29", day: "2025-03-01", want: 21},
+		{name: "born today", birthDate: "2025-06-15", day: "2025-06-15", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AgeOn(date(tt.birthDate), date(tt.day)); got != tt.want {
+				t.Errorf("AgeOn(%s, %s) = %d, want %d", tt.birthDate, tt.day, got, tt.want)
+			}
+		})
+	}
+}
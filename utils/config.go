@@ -3,6 +3,8 @@ package utils
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,13 +15,24 @@ type Config struct {
 	DBUser        string
 	DBPassword    string
 	DBName        string
+	AutoMigrate   bool
 	RedisHost     string
 	RedisPort     string
 	RedisPassword string
-	JWTSecret     string
+	JWT           *JWTConfig
 	Port          string
+	TwoFactor     *TwoFactorConfig
+	OIDC          *OIDCConfig
 	SMTP          *SMTPConfig
 	Admin         *AdminConfig
+	RateLimit     *RateLimitConfig
+	AppURL        string
+	// TrustedProxies may set X-Forwarded-For, the client IP used for rate
+	// limits and audit entries. Empty trusts no proxy.
+	TrustedProxies []string
+	Watchlist      *WatchlistConfig
+	Transaction    *TransactionConfig
+	TMDB           *TMDBConfig
 }
 
 type SMTPConfig struct {
@@ -30,6 +43,51 @@ type SMTPConfig struct {
 	From     string
 }
 
+type JWTConfig struct {
+	KeysDir     string
+	ActiveKeyID string
+}
+
+type TwoFactorConfig struct {
+	Issuer           string
+	RequiredForAdmin bool
+}
+
+type OIDCConfig struct {
+	Provider     string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       string
+}
+
+func (c *OIDCConfig) Enabled() bool {
+	return c.IssuerURL != "" && c.ClientID != ""
+}
+
+// RateLimitConfig holds per-IP request limits. A limit of 0 disables it.
+type RateLimitConfig struct {
+	PublicRequests int
+	Window         time.Duration
+}
+
+type WatchlistConfig struct {
+	ReminderInterval time.Duration
+}
+
+type TransactionConfig struct {
+	ExpiryInterval time.Duration
+}
+
+// TMDBConfig is only needed to fetch catalog fixtures; importing them works
+// offline.
+type TMDBConfig struct {
+	APIKey       string
+	BaseURL      string
+	ImageBaseURL string
+}
+
 type AdminConfig struct {
 	Username string
 	Email    string
@@ -45,11 +103,27 @@ func Load() *Config {
 		DBUser:        getEnv("DB_USER", "postgres"),
 		DBPassword:    getEnv("DB_PASSWORD", ""),
 		DBName:        getEnv("DB_NAME", "postgres"),
+		AutoMigrate:   getEnvBool("AUTO_MIGRATE", true),
 		RedisHost:     getEnv("REDIS_HOST", "localhost"),
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
-		JWTSecret:     getEnv("JWT_SECRET", "secretkey"),
-		Port:          getEnv("PORT", "8080"),
+		JWT: &JWTConfig{
+			KeysDir:     getEnv("JWT_KEYS_DIR", "keys"),
+			ActiveKeyID: getEnv("JWT_ACTIVE_KID", ""),
+		},
+		Port: getEnv("PORT", "8080"),
+		TwoFactor: &TwoFactorConfig{
+			Issuer:           getEnv("TOTP_ISSUER", "NOIR"),
+			RequiredForAdmin: getEnvBool("REQUIRE_ADMIN_2FA", false),
+		},
+		OIDC: &OIDCConfig{
+			Provider:     getEnv("OIDC_PROVIDER", "oidc"),
+			IssuerURL:    getEnv("OIDC_ISSUER_URL", ""),
+			ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:9503/auth/oidc/callback"),
+			Scopes:       getEnv("OIDC_SCOPES", "openid email profile"),
+		},
 		SMTP: &SMTPConfig{
 			Host:     getEnv("SMTP_HOST", "smtp.gmail.com"),
 			Port:     getEnvInt("SMTP_PORT", 587),
@@ -62,6 +136,23 @@ func Load() *Config {
 			Email:    getEnv("ADMIN_EMAIL", "admin@mail.com"),
 			Password: getEnv("ADMIN_PASSWORD", "password"),
 		},
+		RateLimit: &RateLimitConfig{
+			PublicRequests: getEnvInt("RATE_LIMIT_PUBLIC", 120),
+			Window:         time.Duration(getEnvInt("RATE_LIMIT_WINDOW_SECONDS", 60)) * time.Second,
+		},
+		AppURL:         getEnv("APP_URL", "http://localhost:8080"),
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+		Watchlist: &WatchlistConfig{
+			ReminderInterval: time.Duration(getEnvInt("WATCHLIST_REMINDER_MINUTES", 15)) * time.Minute,
+		},
+		Transaction: &TransactionConfig{
+			ExpiryInterval: time.Duration(getEnvInt("TRANSACTION_EXPIRY_SECONDS", 60)) * time.Second,
+		},
+		TMDB: &TMDBConfig{
+			APIKey:       getEnv("TMDB_API_KEY", ""),
+			BaseURL:      getEnv("TMDB_BASE_URL", "https://api.themoviedb.org/3"),
+			ImageBaseURL: getEnv("TMDB_IMAGE_BASE_URL", "https://image.tmdb.org/t/p"),
+		},
 	}
 }
 
@@ -72,6 +163,17 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList splits a comma separated variable, nil when it is unset.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -80,3 +182,12 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
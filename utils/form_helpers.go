@@ -52,3 +52,16 @@ func GetIntArray(form map[string][]string, key string) (*[]int, error) {
 	}
 	return &[]int{}, nil
 }
+
+func GetStringArray(form map[string][]string, key string) *[]string {
+	if val, ok := form[key]; ok && len(val) > 0 {
+		result := []string{}
+		for _, s := range strings.Split(val[0], ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				result = append(result, s)
+			}
+		}
+		return &result
+	}
+	return nil
+}
@@ -1,20 +1,232 @@
 package utils
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateTokens(userID int, role string) (string, error) {
+const TokenTTL = 24 * time.Hour
+
+const minRSAKeyBits = 2048
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// Keyring holds the key used to sign new tokens and every key still accepted
+// for verification. Rotating means adding a new key, switching JWT_ACTIVE_KID
+// to it and removing the old key once all tokens it signed have expired.
+type Keyring struct {
+	active *signingKey
+	keys   map[string]*signingKey
+}
+
+var (
+	keyringOnce sync.Once
+	keyring     *Keyring
+	keyringErr  error
+)
+
+// LoadKeyring reads the keys from JWT_KEYS_DIR once. Private keys are stored as
+// <kid>.pem, verification-only keys of retired signers as <kid>.pub.pem.
+func LoadKeyring() (*Keyring, error) {
+	keyringOnce.Do(func() {
+		cfg := Load().JWT
+		keyring, keyringErr = loadKeyring(cfg.KeysDir, cfg.ActiveKeyID)
+	})
+	return keyring, keyringErr
+}
+
+func loadKeyring(dir, activeKID string) (*Keyring, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwt keys directory %q: %w", dir, err)
+	}
+
+	kr := &Keyring{keys: map[string]*signingKey{}}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt key %s: %w", name, err)
+		}
+
+		var key *signingKey
+		if kid, ok := strings.CutSuffix(name, ".pub.pem"); ok {
+			key, err = parsePublicKey(kid, data)
+		} else {
+			key, err = parsePrivateKey(strings.TrimSuffix(name, ".pem"), data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwt key %s: %w", name, err)
+		}
+
+		if existing, ok := kr.keys[key.kid]; ok && existing.private != nil {
+			continue
+		}
+		kr.keys[key.kid] = key
+	}
+
+	if len(kr.keys) == 0 {
+		return nil, fmt.Errorf("no jwt keys found in %q", dir)
+	}
+
+	if activeKID == "" {
+		return nil, errors.New("JWT_ACTIVE_KID is required")
+	}
+	active, ok := kr.keys[activeKID]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("no private key for active kid %q", activeKID)
+	}
+	kr.active = active
+
+	return kr, nil
+}
+
+func parsePrivateKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("key cannot sign")
+	}
+
+	key, err := newSigningKey(kid, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.private = signer
+	return key, nil
+}
+
+func parsePublicKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newSigningKey(kid, parsed)
+}
+
+func newSigningKey(kid string, public crypto.PublicKey) (*signingKey, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+		}
+		return &signingKey{kid: kid, method: jwt.SigningMethodRS256, public: key}, nil
+	case ed25519.PublicKey:
+		return &signingKey{kid: kid, method: jwt.SigningMethodEdDSA, public: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", public)
+	}
+}
+
+func (kr *Keyring) validMethods() []string {
+	seen := map[string]bool{}
+	methods := []string{}
+	for _, key := range kr.keys {
+		alg := key.method.Alg()
+		if !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// JWKS returns the public keys accepted for verification.
+func (kr *Keyring) JWKS() (JWKS, error) {
+	kids := make([]string, 0, len(kr.keys))
+	for kid := range kr.keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(kids))}
+	for _, kid := range kids {
+		key := kr.keys[kid]
+		jwk, err := NewJWK(key.kid, key.method.Alg(), key.public)
+		if err != nil {
+			return JWKS{}, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// GenerateTokens issues an access token. mfa records whether the session was
+// confirmed with a second factor.
+func GenerateTokens(userID int, role string, mfa bool) (string, error) {
+	kr, err := LoadKeyring()
+	if err != nil {
+		return "", err
+	}
+
+	// iat carries milliseconds so a token issued right after a session
+	// revocation is not mistaken for one issued before it.
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"role":    role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+		"mfa":     mfa,
+		"iat":     float64(now.UnixMilli()) / 1000,
+		"exp":     now.Add(TokenTTL).Unix(),
 	}
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token, err := accessToken.SignedString([]byte(Load().JWTSecret))
+	accessToken := jwt.NewWithClaims(kr.active.method, claims)
+	accessToken.Header["kid"] = kr.active.kid
+	token, err := accessToken.SignedString(kr.active.private)
 	if err != nil {
 		return "", err
 	}
@@ -23,9 +235,22 @@ func GenerateTokens(userID int, role string) (string, error) {
 }
 
 func ValidateToken(tokenString string) (jwt.MapClaims, error) {
+	kr, err := LoadKeyring()
+	if err != nil {
+		return nil, err
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(Load().JWTSecret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, ok := kr.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return key.public, nil
+	}, jwt.WithValidMethods(kr.validMethods()), jwt.WithExpirationRequired())
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid token")
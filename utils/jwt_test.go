@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func writeEd25519Key(t *testing.T, dir, kid string, publicOnly bool) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	if publicOnly {
+		der, err := x509.MarshalPKIXPublicKey(public)
+		if err != nil {
+			t.Fatalf("marshal public key: %v", err)
+		}
+		writePEM(t, dir, kid+".pub.pem", "PUBLIC KEY", der)
+		return
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	writePEM(t, dir, kid+".pem", "PRIVATE KEY", der)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(t *testing.T, dir string)
+		activeKID string
+		wantKIDs  []string
+		wantErr   bool
+	}{
+		{
+			name:      "active key",
+			setup:     func(t *testing.T, dir string) { writeEd25519Key(t, dir, "2025-01", false) },
+			activeKID: "2025-01",
+			wantKIDs:  []string{"2025-01"},
+		},
+		{
+			name: "retired public key is kept for verification",
+			setup: func(t *testing.T, dir string) {
+				writeEd25519Key(t, dir, "2024-06", true)
+				writeEd25519Key(t, dir, "2025-01", false)
+				writePEM(t, dir, "README.txt", "IGNORED", nil)
+			},
+			activeKID: "2025-01",
+			wantKIDs:  []string{"2024-06", "2025-01"},
+		},
+		{
+			name:      "active kid only has a public key",
+			setup:     func(t *testing.T, dir string) { writeEd25519Key(t, dir, "2025-01", true) },
+			activeKID: "2025-01",
+			wantErr:   true,
+		},
+		{
+			name:      "unknown active kid",
+			setup:     func(t *testing.T, dir string) { writeEd25519Key(t, dir, "2025-01", false) },
+			activeKID: "2025-02",
+			wantErr:   true,
+		},
+		{
+			name:      "missing active kid",
+			setup:     func(t *testing.T, dir string) { writeEd25519Key(t, dir, "2025-01", false) },
+			activeKID: "",
+			wantErr:   true,
+		},
+		{
+			name:      "empty directory",
+			setup:     func(t *testing.T, dir string) {},
+			activeKID: "2025-01",
+			wantErr:   true,
+		},
+		{
+			name: "rsa key below minimum size",
+			setup: func(t *testing.T, dir string) {
+				key, err := rsa.GenerateKey(rand.Reader, 1024)
+				if err != nil {
+					t.Fatalf("generate key: %v", err)
+				}
+				writePEM(t, dir, "weak.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+			},
+			activeKID: "weak",
+			wantErr:   true,
+		},
+		{
+			name:      "malformed pem",
+			setup:     func(t *testing.T, dir string) { os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("nope"), 0o600) },
+			activeKID: "broken",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.setup(t, dir)
+
+			kr, err := loadKeyring(dir, tt.activeKID)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadKeyring: %v", err)
+			}
+
+			if kr.active.kid != tt.activeKID {
+				t.Errorf("active kid = %q, want %q", kr.active.kid, tt.activeKID)
+			}
+			set, err := kr.JWKS()
+			if err != nil {
+				t.Fatalf("jwks: %v", err)
+			}
+			if len(set.Keys) != len(tt.wantKIDs) {
+				t.Fatalf("jwks has %d keys, want %d", len(set.Keys), len(tt.wantKIDs))
+			}
+			for i, kid := range tt.wantKIDs {
+				if set.Keys[i].Kid != kid {
+					t.Errorf("jwks key %d = %q, want %q", i, set.Keys[i].Kid, kid)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadKeyringMissingDirectory(t *testing.T) {
+	if _, err := loadKeyring(filepath.Join(t.TempDir(), "missing"), "2025-01"); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+}
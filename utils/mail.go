@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// headerLineBreaks removes line breaks from header values, since a value that
+// ends a line could add headers or start the body.
+var headerLineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// SendMail sends a plain text email through the configured SMTP server.
+func SendMail(to, subject, body string) error {
+	config := Load().SMTP
+
+	msg := buildMailMessage(config.From, to, subject, body)
+
+	auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
+
+	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
+	return smtp.SendMail(addr, auth, config.From, []string{to}, []byte(msg))
+}
+
+// buildMailMessage assembles the message with sanitized headers. The subject
+// is Q-encoded so it may carry user input such as movie titles.
+func buildMailMessage(from, to, subject, body string) string {
+	return fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
+		headerLineBreaks.Replace(from),
+		headerLineBreaks.Replace(to),
+		mime.QEncoding.Encode("utf-8", headerLineBreaks.Replace(subject)),
+		body)
+}
+
+// RenderMailTemplate executes templates/<name> with data.
+func RenderMailTemplate(name string, data any) (string, error) {
+	tmpl, err := template.ParseFiles(filepath.Join("templates", name))
+	if err != nil {
+		return "", fmt.Errorf("error parsing file: %v", err)
+	}
+
+	var body bytes.Buffer
+	err = tmpl.Execute(&body, data)
+	if err != nil {
+		return "", fmt.Errorf("error execute file: %v", err)
+	}
+
+	return body.String(), nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMailMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		to          string
+		subject     string
+		wantHeaders []string
+	}{
+		{
+			name:        "plain subject",
+			to:          "user@mail.com",
+			subject:     "Your tickets",
+			wantHeaders: []string{"From: noir@mail.com", "To: user@mail.com", "Subject: Your tickets"},
+		},
+		{
+			name:        "injected subject header",
+			to:          "user@mail.com",
+			subject:     "Hi\r\nBcc: victim@mail.com",
+			wantHeaders: []string{"From: noir@mail.com", "To: user@mail.com", "Subject: HiBcc: victim@mail.com"},
+		},
+		{
+			name:        "injected recipient header",
+			to:          "user@mail.com\nBcc: victim@mail.com",
+			subject:     "Your tickets",
+			wantHeaders: []string{"From: noir@mail.com", "To: user@mail.comBcc: victim@mail.com", "Subject: Your tickets"},
+		},
+		{
+			name:        "non ascii subject",
+			to:          "user@mail.com",
+			subject:     "Tiket Amélie",
+			wantHeaders: []string{"From: noir@mail.com", "To: user@mail.com", "Subject: =?utf-8?q?Tiket_Am=C3=A9lie?="},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := buildMailMessage("noir@mail.com", tt.to, tt.subject, "body\r\nline")
+
+			headers, body, ok := strings.Cut(msg, "\r\n\r\n")
+			if !ok {
+				t.Fatalf("message has no header separator: %q", msg)
+			}
+			if got := strings.Split(headers, "\r\n"); strings.Join(got, "|") != strings.Join(tt.wantHeaders, "|") {
+				t.Errorf("headers = %q, want %q", got, tt.wantHeaders)
+			}
+			if body != "body\r\nline" {
+				t.Errorf("body = %q", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockKey is the pg_advisory_lock key held while migrating, so
+// instances booting together apply each migration once.
+const migrationLockKey = 7281694312
+
+// migrationDB is satisfied by both a pool and a single connection.
+type migrationDB interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Migration is a numbered pair of up and down scripts such as
+// 000001_create_directors_table.up.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// LoadMigrations reads the migration scripts at the root of fsys, ordered by
+// version.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", entry.Name(), err)
+		}
+		script, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if match[3] == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// SchemaVersion returns the applied migration version, 0 for an empty
+// database. The schema_migrations table is shared with the migrate CLI used by
+// the Makefile.
+func SchemaVersion(ctx context.Context, db migrationDB) (int64, bool, error) {
+	if err := ensureSchemaMigrations(ctx, db); err != nil {
+		return 0, false, err
+	}
+
+	var version int64
+	var dirty bool
+	err := db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return version, dirty, nil
+}
+
+// MigrateUp applies every migration newer than the current version and
+// returns how many were applied.
+func MigrateUp(ctx context.Context, pool *pgxpool.Pool, migrations []Migration) (int, error) {
+	db, unlock, err := lockMigrations(ctx, pool)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	current, dirty, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty, fix it manually before migrating", current)
+	}
+
+	applied := 0
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+		if err := runMigration(ctx, db, migration.Up, &migration.Version); err != nil {
+			return applied, fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+// MigrateDown reverts up to steps migrations starting from the current
+// version and returns how many were reverted.
+func MigrateDown(ctx context.Context, pool *pgxpool.Pool, migrations []Migration, steps int) (int, error) {
+	db, unlock, err := lockMigrations(ctx, pool)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	current, dirty, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty, fix it manually before migrating", current)
+	}
+
+	reverted := 0
+	for i := len(migrations) - 1; i >= 0 && reverted < steps; i-- {
+		migration := migrations[i]
+		if migration.Version > current {
+			continue
+		}
+
+		var previous *int64
+		if i > 0 {
+			previous = &migrations[i-1].Version
+		}
+		if err := runMigration(ctx, db, migration.Down, previous); err != nil {
+			return reverted, fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		reverted++
+	}
+
+	return reverted, nil
+}
+
+// lockMigrations takes the migration lock on a dedicated connection, advisory
+// locks being held per session. Migrations must run on the returned
+// connection, unlock releases both.
+func lockMigrations(ctx context.Context, pool *pgxpool.Pool) (*pgxpool.Conn, func(), error) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		conn.Release()
+		return nil, nil, fmt.Errorf("failed to lock migrations: %w", err)
+	}
+
+	unlock := func() {
+		// A failed unlock leaves the lock to die with the session.
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+	return conn, unlock, nil
+}
+
+// runMigration executes script and records version in one transaction, a nil
+// version means no migration is applied.
+func runMigration(ctx context.Context, db migrationDB, script string, version *int64) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations"); err != nil {
+		return err
+	}
+	if version != nil {
+		_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)", *version)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func ensureSchemaMigrations(ctx context.Context, db migrationDB) error {
+	_, err := db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT NOT NULL PRIMARY KEY,
+			dirty BOOLEAN NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// CheckSchemaVersion fails unless the database is cleanly migrated to the
+// newest of migrations.
+func CheckSchemaVersion(ctx context.Context, db *pgxpool.Pool, migrations []Migration) error {
+	if len(migrations) == 0 {
+		return errors.New("no migrations found")
+	}
+	expected := migrations[len(migrations)-1].Version
+
+	version, dirty, err := SchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database schema version %d is dirty", version)
+	}
+	if version != expected {
+		return fmt.Errorf("database schema is at version %d but this build expects %d", version, expected)
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+var oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type OIDCTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+	TokenType   string `json:"token_type"`
+}
+
+type OIDCClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	GivenName     string `json:"given_name"`
+	FamilyName    string `json:"family_name"`
+	Nonce         string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// oidcDiscoveryTTL bounds how long a discovery document is reused, so
+// endpoint changes at the provider are picked up without a restart.
+const oidcDiscoveryTTL = time.Hour
+
+type cachedDiscovery struct {
+	discovery *OIDCDiscovery
+	fetchedAt time.Time
+}
+
+var (
+	discoveryMu     sync.Mutex
+	discoveryCache  = map[string]cachedDiscovery{}
+	discoveryFlight singleflight.Group
+	jwksMu          sync.Mutex
+	jwksCache       = map[string]map[string]any{}
+)
+
+// DiscoverOIDC fetches and caches the provider's openid-configuration document.
+// Concurrent logins share one fetch, and the lock is never held while it runs.
+func DiscoverOIDC(ctx context.Context, issuer string) (*OIDCDiscovery, error) {
+	discoveryMu.Lock()
+	cached, ok := discoveryCache[issuer]
+	discoveryMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < oidcDiscoveryTTL {
+		return cached.discovery, nil
+	}
+
+	result, err, _ := discoveryFlight.Do(issuer, func() (any, error) {
+		var d OIDCDiscovery
+		wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+		if err := getJSON(ctx, wellKnown, &d); err != nil {
+			return nil, fmt.Errorf("failed to discover oidc provider: %w", err)
+		}
+		if d.Issuer != issuer {
+			return nil, fmt.Errorf("oidc issuer mismatch: got %s", d.Issuer)
+		}
+
+		discoveryMu.Lock()
+		discoveryCache[issuer] = cachedDiscovery{discovery: &d, fetchedAt: time.Now()}
+		discoveryMu.Unlock()
+		return &d, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*OIDCDiscovery), nil
+}
+
+// GeneratePKCE returns a code verifier and its S256 challenge.
+func GeneratePKCE() (verifier, challenge string, err error) {
+	verifier, err = RandomURLToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func RandomURLToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func OIDCAuthorizationURL(d *OIDCDiscovery, cfg *OIDCConfig, state, nonce, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", cfg.ClientID)
+	query.Set("redirect_uri", cfg.RedirectURL)
+	query.Set("scope", cfg.Scopes)
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return d.AuthorizationEndpoint + separator + query.Encode()
+}
+
+func ExchangeOIDCCode(ctx context.Context, d *OIDCDiscovery, cfg *OIDCConfig, code, codeVerifier string) (*OIDCTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("client_id", cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if cfg.ClientSecret != "" {
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+
+	var token OIDCTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return &token, nil
+}
+
+// VerifyOIDCIDToken checks the ID token signature against the provider JWKS and
+// validates issuer, audience, expiry and nonce.
+func VerifyOIDCIDToken(ctx context.Context, d *OIDCDiscovery, clientID, rawIDToken, nonce string) (*OIDCClaims, error) {
+	claims := &OIDCClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return oidcSigningKey(ctx, d.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithAudience(clientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id token: missing subject")
+	}
+
+	return claims, nil
+}
+
+func oidcSigningKey(ctx context.Context, jwksURI, kid string) (any, error) {
+	jwksMu.Lock()
+	defer jwksMu.Unlock()
+
+	if key, ok := jwksCache[jwksURI][kid]; ok {
+		return key, nil
+	}
+
+	// Unknown kid: the provider may have rotated keys, so refresh once.
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := getJSON(ctx, jwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := map[string]any{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	jwksCache[jwksURI] = keys
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no signing key for kid %q", kid)
+	}
+	return key, nil
+}
+
+func getJSON(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "noir-test"
+	testNonce    = "nonce-123"
+	testCode     = "auth-code"
+)
+
+// testProvider is a minimal OIDC provider serving discovery, JWKS and a token
+// endpoint that enforces PKCE.
+type testProvider struct {
+	server      *httptest.Server
+	key         ed25519.PrivateKey
+	challenge   string
+	idToken     string
+	discoveries atomic.Int32
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	jwk, err := NewJWK("test-key", "EdDSA", public)
+	if err != nil {
+		t.Fatalf("new jwk: %v", err)
+	}
+
+	p := &testProvider{key: private}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		p.discoveries.Add(1)
+		json.NewEncoder(w).Encode(OIDCDiscovery{
+			Issuer:                p.server.URL,
+			AuthorizationEndpoint: p.server.URL + "/authorize",
+			TokenEndpoint:         p.server.URL + "/token",
+			JWKSURI:               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(JWKS{Keys: []JWK{jwk}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != testCode ||
+			r.PostForm.Get("client_id") != testClientID ||
+			base64.RawURLEncoding.EncodeToString(sum[:]) != p.challenge {
+			http.Error(w, "invalid_grant", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(OIDCTokenResponse{IDToken: p.idToken, TokenType: "Bearer"})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testProvider) sign(t *testing.T, claims OIDCClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		t.Fatalf("sign id token: %v", err)
+	}
+	return signed
+}
+
+func (p *testProvider) claims() OIDCClaims {
+	return OIDCClaims{
+		Subject: "subject-1",
+		Email:   "user@mail.com",
+		Nonce:   testNonce,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    p.server.URL,
+			Audience:  jwt.ClaimStrings{testClientID},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func TestDiscoverOIDCCachesDocument(t *testing.T) {
+	p := newTestProvider(t)
+	ctx := context.Background()
+
+	first, err := DiscoverOIDC(ctx, p.server.URL)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	second, err := DiscoverOIDC(ctx, p.server.URL)
+	if err != nil {
+		t.Fatalf("discover again: %v", err)
+	}
+
+	if first.TokenEndpoint != p.server.URL+"/token" {
+		t.Errorf("token endpoint = %q", first.TokenEndpoint)
+	}
+	if first != second {
+		t.Error("expected the cached document to be reused")
+	}
+	if n := p.discoveries.Load(); n != 1 {
+		t.Errorf("discovery fetched %d times, want 1", n)
+	}
+}
+
+func TestDiscoverOIDCRejectsIssuerMismatch(t *testing.T) {
+	p := newTestProvider(t)
+
+	if _, err := DiscoverOIDC(context.Background(), p.server.URL+"/"); err == nil {
+		t.Fatal("expected an issuer mismatch error")
+	}
+}
+
+func TestExchangeOIDCCodeVerifiesPKCE(t *testing.T) {
+	p := newTestProvider(t)
+	ctx := context.Background()
+
+	d, err := DiscoverOIDC(ctx, p.server.URL)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatalf("generate pkce: %v", err)
+	}
+	p.challenge = challenge
+	p.idToken = p.sign(t, p.claims())
+	cfg := &OIDCConfig{ClientID: testClientID, RedirectURL: "http://localhost/callback"}
+
+	authURL := OIDCAuthorizationURL(d, cfg, "state", testNonce, challenge)
+	if !strings.Contains(authURL, "code_challenge="+challenge) {
+		t.Errorf("authorization url misses the challenge: %s", authURL)
+	}
+
+	if _, err := ExchangeOIDCCode(ctx, d, cfg, testCode, "wrong-verifier"); err == nil {
+		t.Error("expected the exchange to fail with a wrong verifier")
+	}
+
+	token, err := ExchangeOIDCCode(ctx, d, cfg, testCode, verifier)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+	claims, err := VerifyOIDCIDToken(ctx, d, testClientID, token.IDToken, testNonce)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.Subject != "subject-1" || claims.Email != "user@mail.com" {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestVerifyOIDCIDToken(t *testing.T) {
+	p := newTestProvider(t)
+	d, err := DiscoverOIDC(context.Background(), p.server.URL)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*OIDCClaims)
+		nonce  string
+		valid  bool
+	}{
+		{name: "valid", mutate: func(*OIDCClaims) {}, nonce: testNonce, valid: true},
+		{name: "wrong issuer", mutate: func(c *OIDCClaims) { c.Issuer = "https://evil.example" }, nonce: testNonce},
+		{name: "wrong audience", mutate: func(c *OIDCClaims) { c.Audience = jwt.ClaimStrings{"other-client"} }, nonce: testNonce},
+		{name: "expired", mutate: func(c *OIDCClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }, nonce: testNonce},
+		{name: "missing expiry", mutate: func(c *OIDCClaims) { c.ExpiresAt = nil }, nonce: testNonce},
+		{name: "nonce mismatch", mutate: func(*OIDCClaims) {}, nonce: "other-nonce"},
+		{name: "missing subject", mutate: func(c *OIDCClaims) { c.Subject = "" }, nonce: testNonce},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := p.claims()
+			tt.mutate(&claims)
+
+			_, err := VerifyOIDCIDToken(context.Background(), d, testClientID, p.sign(t, claims), tt.nonce)
+			if tt.valid && err != nil {
+				t.Fatalf("expected a valid token, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Fatal("expected the token to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package utils
+
+const (
+	RoleUser          = "user"
+	RoleAdmin         = "admin"
+	RoleCinemaManager = "cinema_manager"
+	RoleUsher         = "usher"
+	RoleSupport       = "support"
+)
+
+const (
+	PermMovieWrite       = "movie:write"
+	PermShowtimeWrite    = "showtime:write"
+	PermUserRead         = "user:read"
+	PermUserWrite        = "user:write"
+	PermTransactionRead  = "transaction:read"
+	PermTransactionWrite = "transaction:write"
+	PermTicketScan       = "ticket:scan"
+	PermReportRead       = "report:read"
+	PermReviewModerate   = "review:moderate"
+	PermAuditRead        = "audit:read"
+)
+
+var rolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermMovieWrite,
+		PermShowtimeWrite,
+		PermUserRead,
+		PermUserWrite,
+		PermTransactionRead,
+		PermTransactionWrite,
+		PermTicketScan,
+		PermReportRead,
+		PermReviewModerate,
+		PermAuditRead,
+	},
+	RoleCinemaManager: {
+		PermShowtimeWrite,
+		PermTransactionRead,
+		PermTicketScan,
+		PermReportRead,
+	},
+	RoleUsher: {
+		PermTicketScan,
+	},
+	RoleSupport: {
+		PermUserRead,
+		PermTransactionRead,
+		PermTransactionWrite,
+		PermReviewModerate,
+	},
+	RoleUser: {},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+func HasPermission(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
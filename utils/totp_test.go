@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 seed of the RFC 6238 test vectors, base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestValidateTOTP(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	now := time.Unix(1111111109, 0)
+	current := now.Unix() / totpPeriod
+	codeAt := func(step int64) string { return totpCode(key, uint64(step)) }
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		now      time.Time
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "rfc 6238 vector", secret: rfc6238Secret, code: "287082", now: time.Unix(59, 0), wantStep: 1, wantOK: true},
+		{name: "current step", secret: rfc6238Secret, code: codeAt(current), now: now, wantStep: current, wantOK: true},
+		{name: "previous step within skew", secret: rfc6238Secret, code: codeAt(current - 1), now: now, wantStep: current - 1, wantOK: true},
+		{name: "next step within skew", secret: rfc6238Secret, code: codeAt(current + 1), now: now, wantStep: current + 1, wantOK: true},
+		{name: "two steps old", secret: rfc6238Secret, code: codeAt(current - 2), now: now},
+		{name: "two steps ahead", secret: rfc6238Secret, code: codeAt(current + 2), now: now},
+		{name: "lowercase secret and padded code", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: " " + codeAt(current) + " ", now: now, wantStep: current, wantOK: true},
+		{name: "wrong length", secret: rfc6238Secret, code: "12345", now: now},
+		{name: "invalid secret", secret: "not base32!", code: codeAt(current), now: now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := ValidateTOTP(tt.secret, tt.code, tt.now)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("ValidateTOTP() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}